@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/lakshmaji/delivery-shell/clients"
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+	"github.com/lakshmaji/delivery-shell/utils/msg_utils"
+	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
+)
+
+// Lints offers file, fails when any of the issues is an error (warnings are only reported)
+func LintOffersHandler(writer clients.BaseWriter, loader func(string) ([]models.Offer, error), filename string) {
+	offers, err := loader(filename)
+	if err != nil {
+		writer.WriteError(err)
+	}
+	issues := offer_utils.LintOffers(offers)
+	if len(issues) == 0 {
+		writer.Write(msg_utils.MsgOffersLintClean)
+		return
+	}
+	for _, issue := range issues {
+		writer.Write(issue)
+	}
+	if offer_utils.HasLintErrors(issues) {
+		writer.WriteError(error_utils.ErrOffersLint)
+	}
+}
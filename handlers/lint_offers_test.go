@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/clients"
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
+)
+
+func TestLintOffersHandler(t *testing.T) {
+	tt := []struct {
+		description string
+		offers      []models.Offer
+		expected    string
+		fails       bool
+	}{
+		{
+			description: "without issues",
+			offers: []models.Offer{
+				{Code: "OFR001", Discount: 0.1, Conditions: []models.Condition{{Fact: "distance", Operator: models.LessThan, Value: 50}}},
+			},
+			expected: "No issues found\n",
+		},
+		{
+			description: "with warnings only",
+			offers: []models.Offer{
+				{Code: "OFR001", Discount: 0.1, Conditions: []models.Condition{{Fact: "distance", Operator: models.LessThan, Value: 50}}},
+				{Code: "OFR002", Discount: 0.1, Conditions: []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 50}}},
+			},
+			expected: "OFR001 warning overlap: weight/distance region overlaps with OFR002\n",
+		},
+		{
+			description: "with errors",
+			offers: []models.Offer{
+				{Code: "OFR001", Discount: 0.1, Conditions: []models.Condition{{Fact: "distance", Operator: models.LessThan, Value: 50}}},
+				{Code: "OFR001", Discount: 0.1, Conditions: []models.Condition{{Fact: "distance", Operator: models.GreaterThan, Value: 50}}},
+			},
+			expected: "OFR001 error duplicate-code: code is defined 2 times, only the last one is used\nOffers have lint errors",
+			fails:    true,
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.description, func(t *testing.T) {
+			var output bytes.Buffer
+			writer := clients.NewShellWriter(&output, true)
+			loader := func(string) ([]models.Offer, error) { return test.offers, nil }
+
+			defer func() {
+				r := recover()
+				if output.String() != test.expected {
+					t.Errorf("Expected %q, received %q", test.expected, output.String())
+				}
+				if (r != nil) != test.fails {
+					t.Errorf("Expected failure %v, received %v", test.fails, r)
+				}
+			}()
+
+			LintOffersHandler(writer, loader, "offers.json")
+		})
+	}
+}
+
+func TestLintOffersFile(t *testing.T) {
+	var output bytes.Buffer
+	writer := clients.NewShellWriter(&output, true)
+
+	defer func() {
+		expected := "OFR001 error unsatisfiable: distance conditions can never be satisfied together\nOffers have lint errors"
+		if r := recover(); r == nil {
+			t.Error("Should panic")
+		}
+		if output.String() != expected {
+			t.Errorf("Expected %q, received %q", expected, output.String())
+		}
+	}()
+
+	LintOffersHandler(writer, offer_utils.LoadOffersLenient, "../utils/offer_utils/testdata/lint.json")
+}
+
+func TestLintInvalidOffersFile(t *testing.T) {
+	var output bytes.Buffer
+	writer := clients.NewShellWriter(&output, true)
+
+	// every issue is reported, although the offers are not valid as per schema
+	defer func() {
+		expected := "OFR001 error discount-range: discount should be between 0 and 1, received 1.5\n"
+		expected += "OFR002 error discount-range: discount should be between 0 and 1, received -0.2\n"
+		expected += "OFR002 error unsatisfiable: weight conditions can never be satisfied together\n"
+		expected += "Offers have lint errors"
+		if r := recover(); r == nil {
+			t.Error("Should panic")
+		}
+		if output.String() != expected {
+			t.Errorf("Expected %q, received %q", expected, output.String())
+		}
+	}()
+
+	LintOffersHandler(writer, offer_utils.LoadOffersLenient, "../utils/offer_utils/testdata/lint_invalid.json")
+}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"errors"
+	"io/ioutil"
+
 	"github.com/lakshmaji/delivery-shell/clients"
 	"github.com/lakshmaji/delivery-shell/models"
 	"github.com/lakshmaji/delivery-shell/services/delivery_svc"
@@ -10,28 +13,86 @@ import (
 	"github.com/lakshmaji/delivery-shell/utils/error_utils"
 )
 
+// Optional behaviour of the package handler
+type PackageHandlerOptions struct {
+	Explain bool         // why every offer code did or did not apply, printed under the package row
+	Fleet   models.Fleet // vehicles (ex: from a fleet file), read from the input when not specified
+	// trips of every vehicle, printed after the packages in the given format (models.ManifestText, models.ManifestJSON)
+	Manifest string
+	// file the manifest is written to instead, so that it can be parsed (ex: json manifest)
+	ManifestFile string
+}
+
 func PackageHandler(writer clients.BaseWriter, boxService delivery_svc.DeliveryService, packageInputSvc shell_io_svc.PackageInputService) {
-	computesDeliveryTime, baseDeliveryCost, packages, noOfVehicles, maxSpeed, maxWeightCapacity := readInputs(writer, packageInputSvc)
+	PackageHandlerWithOptions(writer, boxService, packageInputSvc, PackageHandlerOptions{})
+}
+
+func PackageHandlerWithOptions(writer clients.BaseWriter, boxService delivery_svc.DeliveryService, packageInputSvc shell_io_svc.PackageInputService, options PackageHandlerOptions) {
+	computesDeliveryTime, baseDeliveryCost, packages, fleet := readInputs(writer, packageInputSvc, options.Fleet)
 
 	for _, box := range packages {
 		if !box.IsValid() {
 			writer.WriteError(error_utils.ErrPackageDetailsInValid)
 		}
-		if computesDeliveryTime {
-			if box.Weight > float64(maxWeightCapacity) {
-				writer.WriteError(error_utils.ErrVehicleMaxWeightCapacity(box, maxWeightCapacity))
-			}
+	}
+
+	var manifest models.Manifest
+	if computesDeliveryTime {
+		// every package heavier than the largest vehicle is reported
+		if overweight := boxService.OverweightPackages(packages, fleet); len(overweight) > 0 {
+			writer.WriteError(error_utils.ErrVehiclesMaxWeightCapacity(overweight, fleet.MaxCapacity()))
+		}
+		// clone pointer variable packages without modifying the original
+		boxesClone := make(models.Shipment, len(packages))
+		copy(boxesClone, packages)
+		var unshipped []*models.PackageDetails
+		manifest, unshipped = boxService.PlanTrips(boxesClone, fleet)
+		if len(unshipped) > 0 {
+			writer.WriteError(error_utils.ErrPackagesNotShipped(unshipped))
 		}
 	}
 
-	packageStats, err := handlePackageStats(boxService, packages, baseDeliveryCost, noOfVehicles, maxSpeed, maxWeightCapacity, computesDeliveryTime)
+	packageStats, err := handlePackageStats(boxService, packages, baseDeliveryCost, manifest, computesDeliveryTime, options)
 	if err != nil {
+		// discounts of the failed run are not redeemed
+		boxService.ReleaseRedemptions()
+		writer.WriteError(err)
+	}
+	if err := boxService.CommitRedemptions(); err != nil {
 		writer.WriteError(err)
 	}
 	writer.Write(packageStats.FmtOutput(computesDeliveryTime))
+	if computesDeliveryTime {
+		writeManifest(writer, manifest, options.Manifest, options.ManifestFile)
+	}
 }
 
-func readInputs(writer clients.BaseWriter, packageInputSvc shell_io_svc.PackageInputService) (computesDeliveryTime bool, baseDeliveryCost models.BaseDeliveryCost, packages []*models.PackageDetails, noOfVehicles, maxSpeed, maxWeightCapacity int) {
+// Trips of every vehicle in the given format, to the file when specified (otherwise after the packages)
+// nothing when the format is not specified
+func writeManifest(writer clients.BaseWriter, manifest models.Manifest, format string, filename string) {
+	var output string
+	switch format {
+	case models.ManifestText:
+		output = manifest.FmtOutput()
+	case models.ManifestJSON:
+		var err error
+		output, err = manifest.FmtJSON()
+		if err != nil {
+			writer.WriteError(err)
+		}
+	default:
+		return
+	}
+	if filename == "" {
+		writer.Write(output)
+		return
+	}
+	if err := ioutil.WriteFile(filename, []byte(output), 0644); err != nil {
+		writer.WriteError(err)
+	}
+}
+
+func readInputs(writer clients.BaseWriter, packageInputSvc shell_io_svc.PackageInputService, knownFleet models.Fleet) (computesDeliveryTime bool, baseDeliveryCost models.BaseDeliveryCost, packages []*models.PackageDetails, fleet models.Fleet) {
 	var err error
 	var noOfPackages int
 	var timeComputeDecisionInput string
@@ -48,8 +109,9 @@ func readInputs(writer clients.BaseWriter, packageInputSvc shell_io_svc.PackageI
 	if err != nil {
 		writer.WriteError(err)
 	}
-	if computesDeliveryTime {
-		noOfVehicles, maxSpeed, maxWeightCapacity, err = packageInputSvc.ScanVehicleDetails(writer)
+	fleet = knownFleet
+	if computesDeliveryTime && len(fleet) == 0 {
+		fleet, err = packageInputSvc.ScanVehicleDetails(writer)
 		if err != nil {
 			writer.WriteError(err)
 		}
@@ -58,36 +120,53 @@ func readInputs(writer clients.BaseWriter, packageInputSvc shell_io_svc.PackageI
 }
 
 // Computes discounts, est delivery time
-func handlePackageStats(boxService delivery_svc.DeliveryService, boxes []*models.PackageDetails, baseDeliveryCost models.BaseDeliveryCost, noOfVehicles int, maxSpeed int, maxWeightCapacity int, computesDeliveryTime bool) (models.PackageStatsList, error) {
+func handlePackageStats(boxService delivery_svc.DeliveryService, boxes []*models.PackageDetails, baseDeliveryCost models.BaseDeliveryCost, manifest models.Manifest, computesDeliveryTime bool, options PackageHandlerOptions) (models.PackageStatsList, error) {
 	var packageStats []models.PackageStats
 
-	// clone pointer variable boxes without modifying the original
-	boxesClone := make(models.Shipment, len(boxes))
-	copy(boxesClone, boxes)
-
-	var itemsDeliveryTime models.PackageDeliveryTime
-	if computesDeliveryTime {
-		// calculate est time
-		itemsDeliveryTime = boxService.EstDeliveryTime(boxesClone, int(maxWeightCapacity), noOfVehicles, maxSpeed)
-	}
+	// est time as per the planned trips
+	itemsDeliveryTime := manifest.DeliveryTimes()
 
 	for _, pkg := range boxes {
 		weight := pkg.Weight
 		distance := pkg.Distance
-		code := pkg.Code
 		// TODO: these 3 methods can be refactored to a single method
 		// get delivery cost
 		deliveryCost := boxService.CalculateDeliveryCost(weight, distance, baseDeliveryCost)
 		// Apply offer code if applicable
-		discount, err := boxService.CalculateDiscount(weight, distance, code, deliveryCost)
+		fact := models.Fact{
+			Weight:           weight,
+			Distance:         distance,
+			BaseDeliveryCost: float64(baseDeliveryCost),
+			DeliveryCost:     deliveryCost.Float64(),
+			PackageCount:     len(boxes),
+			EstDeliveryTime:  itemsDeliveryTime[pkg.Id],
+			Customer:         pkg.Customer,
+		}
+		offers, err := boxService.CalculateDiscounts(fact, pkg.OfferCodes(), deliveryCost)
+		if errors.Is(err, error_utils.ErrUnknownOfferCode) {
+			return nil, error_utils.ErrPackageOffer(pkg.Id, err)
+		}
 		if err != nil {
 			return nil, error_utils.ErrCalculateDiscount
 		}
+		discount := models.TotalDiscount(offers)
 		totalDeliveryCost := delivery_utils.TotalDeliveryCost(deliveryCost, discount)
-		packageStat := models.PackageStats{Id: pkg.Id, Discount: discount, TotalDeliveryCost: totalDeliveryCost}
+		packageStat := models.PackageStats{
+			Id:                pkg.Id,
+			Discount:          discount,
+			TotalDeliveryCost: totalDeliveryCost,
+			Breakdown:         boxService.DeliveryCostBreakdown(weight, distance),
+			Offers:            offers,
+		}
 		if computesDeliveryTime {
 			packageStat.EstDeliveryTime = itemsDeliveryTime[pkg.Id]
 		}
+		if options.Explain {
+			packageStat.Explanations, err = boxService.ExplainDiscounts(fact, pkg.OfferCodes(), offers)
+			if err != nil {
+				return nil, error_utils.ErrCalculateDiscount
+			}
+		}
 		packageStats = append(packageStats, packageStat)
 	}
 	return packageStats, nil
@@ -18,7 +18,7 @@ type mockInputServiceData struct {
 	ErrScanVehicleDetails           error
 	noOfVehicles                    int
 	speed                           int
-	maxWeight                       int
+	maxWeight                       models.Weight
 	ErrScanProgramChoice            error
 	choice                          string
 }
@@ -33,7 +33,7 @@ type mockDeliveryPrgmInputs struct {
 	ErrScanVehicleDetails           error
 	noOfVehicles                    int
 	speed                           int
-	maxWeight                       int
+	maxWeight                       models.Weight
 	ErrScanProgramChoice            error
 	choice                          string
 }
@@ -71,11 +71,11 @@ func (d *mockDeliveryPrgmInputs) ScanNPackageDetails(writer clients.BaseWriter,
 	return d.boxes, nil
 }
 
-func (d *mockDeliveryPrgmInputs) ScanVehicleDetails(writer clients.BaseWriter) (int, int, int, error) {
+func (d *mockDeliveryPrgmInputs) ScanVehicleDetails(writer clients.BaseWriter) (models.Fleet, error) {
 	if d.ErrScanVehicleDetails != nil {
-		return 0, 0, 0, d.ErrScanVehicleDetails
+		return nil, d.ErrScanVehicleDetails
 	}
-	return d.noOfVehicles, d.speed, d.maxWeight, nil
+	return models.NewFleet(d.noOfVehicles, d.speed, d.maxWeight), nil
 }
 
 func (d *mockDeliveryPrgmInputs) ScanProgramChoice(writer clients.BaseWriter) (string, error) {
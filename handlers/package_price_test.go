@@ -2,15 +2,19 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/lakshmaji/delivery-shell/clients"
 	"github.com/lakshmaji/delivery-shell/models"
 	"github.com/lakshmaji/delivery-shell/services/delivery_svc"
+	"github.com/lakshmaji/delivery-shell/services/ledger_svc"
 	"github.com/lakshmaji/delivery-shell/services/offers_svc"
 	"github.com/lakshmaji/delivery-shell/utils/error_utils"
 )
@@ -156,13 +160,15 @@ func mockIO(t testing.TB) (*os.File, *bytes.Buffer, clients.BaseWriter, delivery
 			return offersSlice, nil
 
 		},
+		"offers.json",
+		models.RoundHalfUp,
 	)
 	reader, err := ioutil.TempFile("", "")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	mockPkgDeliveryComputeService := delivery_svc.NewDeliveryService(mockOffersSvc)
+	mockPkgDeliveryComputeService := delivery_svc.NewDeliveryService(mockOffersSvc, models.DefaultRateCard())
 	mockWriter := clients.NewShellWriter(&output, true)
 	return reader, &output, mockWriter, mockPkgDeliveryComputeService
 }
@@ -245,7 +251,7 @@ func TestDeliveryTimeEstimate(t *testing.T) {
 		ErrScanVehicleDetails           error
 		noOfVehicles                    int
 		speed                           int
-		maxWeight                       int
+		maxWeight                       models.Weight
 		ErrScanProgramChoice            error
 		choice                          string
 	}{
@@ -301,7 +307,7 @@ func TestDeliveryTimeEstimate(t *testing.T) {
 			noOfVehicles: 2,
 			speed:        70,
 			maxWeight:    6,
-			expected:     "Package Id, Discount, Total Delivery Cost, Total Est Time\nPKG1, 0.00, 280.00, 0.42\nPKG2, 0.00, 745.00, 1.78\nPKG3, 0.00, 630.00, 6.68\nPKG4, 0.00, 440.00, 4.41\nPKG5, 0.00, 585.00, 1.35\nPKG6, 0.00, 625.00, 4.05\nPKG7, 0.00, 635.00, 1.35\n\n",
+			expected:     "Package Id, Discount, Total Delivery Cost, Total Est Time\nPKG1, 0.00, 280.00, 0.42\nPKG2, 0.00, 745.00, 1.78\nPKG3, 0.00, 630.00, 6.68\n  OFR008 unknown code, did you mean OFR001?\nPKG4, 0.00, 440.00, 4.41\nPKG5, 0.00, 585.00, 1.35\nPKG6, 0.00, 625.00, 4.05\nPKG7, 0.00, 635.00, 1.35\n\n",
 		},
 		{
 			choice:           "yes",
@@ -389,7 +395,7 @@ func TestDeliveryTimeEstimateFail(t *testing.T) {
 		ErrScanVehicleDetails           error
 		noOfVehicles                    int
 		speed                           int
-		maxWeight                       int
+		maxWeight                       models.Weight
 		ErrScanProgramChoice            error
 		choice                          string
 	}{
@@ -435,7 +441,8 @@ func TestDeliveryTimeEstimateFail(t *testing.T) {
 			noOfVehicles: 2,
 			speed:        70,
 			maxWeight:    5,
-			expected:     errors.New("Box PKG2 weight 15.000000 exceed vehicle max weight capacity of 5"),
+			// every package heavier than the capacity is reported
+			expected: errors.New("Box PKG2 weight 15.000000 exceed vehicle max weight capacity of 5\nBox PKG3 weight 10.000000 exceed vehicle max weight capacity of 5"),
 		},
 	}
 
@@ -474,3 +481,306 @@ func TestDeliveryTimeEstimateFail(t *testing.T) {
 	}
 
 }
+
+func TestPkgDiscountExplain(t *testing.T) {
+	reader, output, mockWriter, mockPkgDeliveryComputeService := mockIO(t)
+	defer reader.Close()
+
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 5, Distance: 5, Code: "OFR001"},
+		{Id: "PKG2", Weight: 10, Distance: 100, Code: "OFR009"},
+	}
+	inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 2, boxes: packages, choice: "no"})
+
+	PackageHandlerWithOptions(mockWriter, mockPkgDeliveryComputeService, inputSvc, PackageHandlerOptions{Explain: true})
+
+	expected := "Package Id, Discount, Total Delivery Cost\n"
+	expected += "PKG1, 0.00, 175.00\n"
+	expected += "  OFR001 notApplicable: conditions are not satisfied\n"
+	expected += "    pass distance lessThan 200 (actual 5)\n"
+	expected += "    fail weight greaterThanOrEqual 70 (actual 5)\n"
+	expected += "    pass weight lessThanOrEqual 200 (actual 5)\n"
+	expected += "PKG2, 0.00, 700.00\n"
+	expected += "  OFR009 unknown code, did you mean OFR001?\n\n"
+	if output.String() != expected {
+		t.Errorf("Expected %v, got %v", expected, output.String())
+	}
+}
+
+func TestDeliveryTimeEstimateFleet(t *testing.T) {
+	reader, output, mockWriter, mockPkgDeliveryComputeService := mockIO(t)
+	defer reader.Close()
+
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 5, Distance: 5, Code: "NA"},
+		{Id: "PKG2", Weight: 15, Distance: 5, Code: "NA"},
+		{Id: "PKG3", Weight: 150, Distance: 100, Code: "NA"},
+	}
+	fleet := models.Fleet{
+		{Id: "BIKE1", Type: "bike", Capacity: 20, Speed: 40},
+		{Id: "TRUCK1", Type: "truck", Capacity: 200, Speed: 50, AvailableAt: 1},
+	}
+	// vehicles are not read from the input, when the fleet is known
+	inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 3, boxes: packages, choice: "yes", ErrScanVehicleDetails: error_utils.ErrVehicleDetailsFormat})
+
+	PackageHandlerWithOptions(mockWriter, mockPkgDeliveryComputeService, inputSvc, PackageHandlerOptions{Fleet: fleet})
+
+	expected := "Package Id, Discount, Total Delivery Cost, Total Est Time\n"
+	expected += "PKG1, 0.00, 175.00, 0.12\n"
+	expected += "PKG2, 0.00, 275.00, 0.12\n"
+	expected += "PKG3, 0.00, 2100.00, 3.00\n\n"
+	if output.String() != expected {
+		t.Errorf("Expected %v, got %v", expected, output.String())
+	}
+}
+
+func TestDeliveryTimeEstimateWeightResolution(t *testing.T) {
+	var output bytes.Buffer
+	mockWriter := clients.NewShellWriter(&output, true)
+	offersSvc := offers_svc.NewOffersService(func(string) ([]models.Offer, error) { return offersSlice, nil }, "offers.json", models.RoundHalfUp)
+	deliverySvc := delivery_svc.NewDeliveryServiceWithOptions(offersSvc, models.DefaultRateCard(), delivery_svc.Options{WeightResolution: 0.1})
+
+	// 2001 units (weight rounded up) do not fit 2000 units (capacity rounded down)
+	packages := []*models.PackageDetails{{Id: "PKG1", Weight: 200.05, Distance: 10, Code: "NA"}}
+	fleet := models.Fleet{{Id: "T1", Type: "truck", Capacity: 200.09, Speed: 10}}
+	inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 1, boxes: packages, choice: "yes"})
+
+	defer func() {
+		r := recover()
+		expected := "Box PKG1 weight 200.050000 exceed vehicle max weight capacity of 200.09"
+		if output.String() != expected {
+			t.Errorf("Expected %v, received %v", expected, output.String())
+		}
+		if r == nil {
+			t.Errorf("Should panic")
+		}
+	}()
+	PackageHandlerWithOptions(mockWriter, deliverySvc, inputSvc, PackageHandlerOptions{Fleet: fleet})
+}
+
+// Ships nothing at all
+type noShipmentStrategy struct{}
+
+func (noShipmentStrategy) Name() string {
+	return "none"
+}
+
+func (noShipmentStrategy) PickShipment(pending []delivery_svc.PendingPackage, capacity int) []bool {
+	return make([]bool, len(pending))
+}
+
+func TestDeliveryTimeEstimateUnshipped(t *testing.T) {
+	var output bytes.Buffer
+	mockWriter := clients.NewShellWriter(&output, true)
+	offersSvc := offers_svc.NewOffersService(func(string) ([]models.Offer, error) { return offersSlice, nil }, "offers.json", models.RoundHalfUp)
+	deliverySvc := delivery_svc.NewDeliveryServiceWithOptions(offersSvc, models.DefaultRateCard(), delivery_svc.Options{Strategy: noShipmentStrategy{}})
+
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 5, Distance: 5, Code: "NA"},
+		{Id: "PKG2", Weight: 15, Distance: 5, Code: "NA"},
+	}
+	inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 2, boxes: packages, choice: "yes", noOfVehicles: 1, speed: 70, maxWeight: 200})
+
+	defer func() {
+		r := recover()
+		expected := "Packages PKG1, PKG2 could not be shipped by any vehicle"
+		if output.String() != expected {
+			t.Errorf("Expected %v, received %v", expected, output.String())
+		}
+		if r == nil {
+			t.Errorf("Should panic")
+		}
+	}()
+	PackageHandler(mockWriter, deliverySvc, inputSvc)
+}
+
+func TestDeliveryTimeEstimateManifest(t *testing.T) {
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 5, Distance: 5, Code: "NA"},
+		{Id: "PKG2", Weight: 15, Distance: 5, Code: "NA"},
+		{Id: "PKG3", Weight: 150, Distance: 100, Code: "NA"},
+	}
+	fleet := models.Fleet{
+		{Id: "BIKE1", Type: "bike", Capacity: 20, Speed: 40},
+		{Id: "TRUCK1", Type: "truck", Capacity: 200, Speed: 50, AvailableAt: 1},
+	}
+	stats := "Package Id, Discount, Total Delivery Cost, Total Est Time\nPKG1, 0.00, 175.00, 0.12\nPKG2, 0.00, 275.00, 0.12\nPKG3, 0.00, 2100.00, 3.00\n\n"
+
+	reader, output, mockWriter, mockPkgDeliveryComputeService := mockIO(t)
+	defer reader.Close()
+	tt := []struct {
+		format   string
+		choice   string
+		expected string
+	}{
+		{format: "", choice: "yes", expected: stats},
+		{
+			format: models.ManifestText,
+			choice: "yes",
+			expected: stats + "Vehicle, Trip, Departure, Packages, Load, Furthest Distance, Return\n" +
+				"BIKE1 (bike), 1, 0.00, PKG2 PKG1, 20.00, 5.00, 0.24\n" +
+				"TRUCK1 (truck), 1, 1.00, PKG3, 150.00, 100.00, 5.00\n\n",
+		},
+		// without delivery time, there are no trips
+		{format: models.ManifestText, choice: "no", expected: "Package Id, Discount, Total Delivery Cost\nPKG1, 0.00, 175.00\nPKG2, 0.00, 275.00\nPKG3, 0.00, 2100.00\n\n"},
+	}
+	for _, test := range tt {
+		t.Run(test.format+" "+test.choice, func(t *testing.T) {
+			output.Truncate(0)
+			inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 3, boxes: packages, choice: test.choice})
+
+			PackageHandlerWithOptions(mockWriter, mockPkgDeliveryComputeService, inputSvc, PackageHandlerOptions{Fleet: fleet, Manifest: test.format})
+
+			if output.String() != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, output.String())
+			}
+		})
+	}
+}
+
+func TestDeliveryTimeEstimateManifestFile(t *testing.T) {
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 5, Distance: 5, Code: "NA"},
+		{Id: "PKG2", Weight: 150, Distance: 100, Code: "NA"},
+	}
+	fleet := models.Fleet{{Id: "TRUCK1", Type: "truck", Capacity: 200, Speed: 50}, {Id: "BIKE1", Type: "bike", Capacity: 20, Speed: 40}}
+	filename := filepath.Join(t.TempDir(), "manifest.json")
+
+	reader, output, mockWriter, mockPkgDeliveryComputeService := mockIO(t)
+	defer reader.Close()
+	inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 2, boxes: packages, choice: "yes"})
+
+	PackageHandlerWithOptions(mockWriter, mockPkgDeliveryComputeService, inputSvc, PackageHandlerOptions{Fleet: fleet, Manifest: models.ManifestJSON, ManifestFile: filename})
+
+	// only the packages are printed
+	expected := "Package Id, Discount, Total Delivery Cost, Total Est Time\nPKG1, 0.00, 175.00, 0.10\nPKG2, 0.00, 2100.00, 2.00\n\n"
+	if output.String() != expected {
+		t.Errorf("Expected %v, got %v", expected, output.String())
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest models.Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatalf("manifest should be json, received %v: %s", err, content)
+	}
+	if len(manifest) != 2 || len(manifest[0].Trips) != 1 || len(manifest[0].Trips[0].Packages) != 2 || len(manifest[1].Trips) != 0 {
+		t.Errorf("expected both packages on a single trip of TRUCK1, received %+v", manifest)
+	}
+	if delivered := manifest.DeliveryTimes(); delivered["PKG1"] != 0.1 || delivered["PKG2"] != 2 {
+		t.Errorf("expected est delivery time of every package, received %v", delivered)
+	}
+}
+
+func TestPkgDiscountStrict(t *testing.T) {
+	var output bytes.Buffer
+	mockWriter := clients.NewShellWriter(&output, true)
+	ledger := ledger_svc.NewMemoryLedger()
+	strictOffersSvc := offers_svc.NewOffersServiceWithOptions(func(string) ([]models.Offer, error) { return offersSlice, nil }, "offers.json", models.RoundHalfUp, offers_svc.Options{Strict: true, Ledger: ledger})
+	deliverySvc := delivery_svc.NewDeliveryService(strictOffersSvc, models.DefaultRateCard())
+
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 10, Distance: 100, Code: "OFR003"},
+		{Id: "PKG2", Weight: 10, Distance: 100, Code: "OFR0O3"},
+	}
+	inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 2, boxes: packages, choice: "no"})
+
+	defer func() {
+		r := recover()
+		expected := "Package PKG2 error: Unknown offer code OFR0O3, did you mean OFR003?"
+		if output.String() != expected {
+			t.Errorf("Expected %v, received %v", expected, output.String())
+		}
+		if r == nil {
+			t.Errorf("Should panic")
+		}
+		// discount of PKG1 is not redeemed, as the run failed
+		if total, _ := ledger.Redemptions("OFR003", ""); total != 0 {
+			t.Errorf("Expected redemptions to be released, received %d", total)
+		}
+	}()
+	PackageHandler(mockWriter, deliverySvc, inputSvc)
+}
+
+func TestPkgDiscountRedemptions(t *testing.T) {
+	var output bytes.Buffer
+	mockWriter := clients.NewShellWriter(&output, true)
+	ledger := ledger_svc.NewMemoryLedger()
+	limited := []models.Offer{{Code: "OFR003", Discount: 0.05, Conditions: offersSlice[2].Conditions, MaxRedemptions: 1}}
+	offersSvc := offers_svc.NewOffersServiceWithOptions(func(string) ([]models.Offer, error) { return limited, nil }, "offers.json", models.RoundHalfUp, offers_svc.Options{Ledger: ledger})
+	deliverySvc := delivery_svc.NewDeliveryService(offersSvc, models.DefaultRateCard())
+
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 10, Distance: 100, Code: "OFR003"},
+		{Id: "PKG2", Weight: 10, Distance: 100, Code: "OFR003"},
+	}
+	inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 2, boxes: packages, choice: "no"})
+
+	PackageHandler(mockWriter, deliverySvc, inputSvc)
+
+	expected := "Package Id, Discount, Total Delivery Cost\nPKG1, 35.00, 665.00\nPKG2, 0.00, 700.00\n\n"
+	if output.String() != expected {
+		t.Errorf("Expected %v, got %v", expected, output.String())
+	}
+	if total, _ := ledger.Redemptions("OFR003", ""); total != 1 {
+		t.Errorf("Expected 1 committed redemption, received %d", total)
+	}
+}
+
+func TestPkgDiscountExplainRedemptions(t *testing.T) {
+	var output bytes.Buffer
+	mockWriter := clients.NewShellWriter(&output, true)
+	limited := []models.Offer{{Code: "OFR003", Discount: 0.05, Conditions: offersSlice[2].Conditions, MaxRedemptions: 1}}
+	offersSvc := offers_svc.NewOffersServiceWithOptions(func(string) ([]models.Offer, error) { return limited, nil }, "offers.json", models.RoundHalfUp, offers_svc.Options{})
+	deliverySvc := delivery_svc.NewDeliveryService(offersSvc, models.DefaultRateCard())
+
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 10, Distance: 100, Code: "OFR003"},
+		{Id: "PKG2", Weight: 10, Distance: 100, Code: "OFR003"},
+	}
+	inputSvc := newInputSvc(mockInputServiceData{reader: &bytes.Buffer{}, baseDeliveryCost: 100, noOfPackages: 2, boxes: packages, choice: "no"})
+
+	PackageHandlerWithOptions(mockWriter, deliverySvc, inputSvc, PackageHandlerOptions{Explain: true})
+
+	// the redemption of PKG1 is the only one (it does not exceed the limit), PKG2 exceeds it
+	conditions := "    pass distance greaterThanOrEqual 50 (actual 100)\n"
+	conditions += "    pass distance lessThanOrEqual 250 (actual 100)\n"
+	conditions += "    pass weight greaterThanOrEqual 10 (actual 10)\n"
+	conditions += "    pass weight lessThanOrEqual 150 (actual 10)\n"
+	expected := "Package Id, Discount, Total Delivery Cost\n"
+	expected += "PKG1, 35.00, 665.00\n"
+	expected += "  OFR003 applicable\n" + conditions
+	expected += "PKG2, 0.00, 700.00\n"
+	expected += "  OFR003 limitReached: limited to 1 redemptions\n" + conditions + "\n"
+	if output.String() != expected {
+		t.Errorf("Expected %v, got %v", expected, output.String())
+	}
+}
+
+func TestPackageStatsBreakdown(t *testing.T) {
+	reader, _, _, mockPkgDeliveryComputeService := mockIO(t)
+	defer reader.Close()
+
+	packages := []*models.PackageDetails{
+		{
+			Id:       "PKG1",
+			Weight:   5,
+			Distance: 5,
+			Code:     "OFR001",
+		},
+	}
+
+	packageStats, err := handlePackageStats(mockPkgDeliveryComputeService, packages, 100, nil, false, PackageHandlerOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.SlabCharge{
+		{Fact: "weight", Units: 5, Rate: 10, Amount: 5000},
+		{Fact: "distance", Units: 5, Rate: 5, Amount: 2500},
+	}
+	if !reflect.DeepEqual(packageStats[0].Breakdown, expected) {
+		t.Errorf("Expected %v, received %v", expected, packageStats[0].Breakdown)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"io"
+
+	"github.com/lakshmaji/delivery-shell/clients"
+	"github.com/lakshmaji/delivery-shell/services/shell_io_svc"
+	"github.com/lakshmaji/delivery-shell/services/simulation_svc"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+// Reports what the proposed offers would cost compared to the current offers, over a batch of packages
+// The batch has the same shape as the package input (base delivery cost and no of packages, followed by package details)
+func SimulateOffersHandler(writer clients.BaseWriter, simulationSvc simulation_svc.SimulationService, batchInputSvc shell_io_svc.PackageInputService) {
+	// prompts are not part of the report
+	prompts := clients.NewShellWriter(io.Discard, false)
+	baseDeliveryCost, noOfPackages, err := batchInputSvc.ScanBaseDeliveryCostPkgCount(prompts)
+	if err != nil {
+		writer.WriteError(err)
+	}
+	packages, err := batchInputSvc.ScanNPackageDetails(prompts, noOfPackages)
+	if err != nil {
+		writer.WriteError(err)
+	}
+	for _, box := range packages {
+		if !box.IsValid() {
+			writer.WriteError(error_utils.ErrPackageDetailsInValid)
+		}
+	}
+
+	simulation, err := simulationSvc.SimulateOffers(baseDeliveryCost, packages)
+	if err != nil {
+		writer.WriteError(err)
+	}
+	writer.Write(simulation.FmtOutput())
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/clients"
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/services/delivery_svc"
+	"github.com/lakshmaji/delivery-shell/services/offers_svc"
+	"github.com/lakshmaji/delivery-shell/services/shell_io_svc"
+	"github.com/lakshmaji/delivery-shell/services/simulation_svc"
+)
+
+func TestSimulateOffersHandler(t *testing.T) {
+	proposedSlice := []models.Offer{
+		{Code: "OFR003", Discount: 0.1, Conditions: offersSlice[2].Conditions},
+	}
+	deliveryService := func(offers []models.Offer) delivery_svc.DeliveryService {
+		load := func(string) ([]models.Offer, error) { return offers, nil }
+		return delivery_svc.NewDeliveryService(offers_svc.NewOffersService(load, "offers.json", models.RoundHalfUp), models.DefaultRateCard())
+	}
+	simulationSvc := simulation_svc.NewSimulationService(deliveryService(offersSlice), deliveryService(proposedSlice))
+
+	tt := []struct {
+		description string
+		batch       string
+		expected    string
+		fails       bool
+	}{
+		{
+			description: "sample batch",
+			batch:       "100 3\nPKG1 5 5 OFR001\nPKG2 15 5 OFR002\nPKG3 10 100 OFR003\n",
+			expected: "Offer Code, Current Packages, Current Discount, Current Avg Discount, Proposed Packages, Proposed Discount, Proposed Avg Discount, Discount Difference\n" +
+				"OFR001, 0, 0.00, 0.00, 0, 0.00, 0.00, 0.00\n" +
+				"OFR002, 0, 0.00, 0.00, 0, 0.00, 0.00, 0.00\n" +
+				"OFR003, 1, 35.00, 35.00, 1, 70.00, 70.00, +35.00\n" +
+				"Total (3 packages), 1, 35.00, 35.00, 1, 70.00, 70.00, +35.00\n\n",
+		},
+		{
+			description: "batch with missing packages",
+			batch:       "100 2\nPKG1 5 5 OFR001\n",
+			expected:    "Missing input",
+			fails:       true,
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.description, func(t *testing.T) {
+			var output bytes.Buffer
+			writer := clients.NewShellWriter(&output, true)
+
+			defer func() {
+				r := recover()
+				if output.String() != test.expected {
+					t.Errorf("Expected %q, received %q", test.expected, output.String())
+				}
+				if (r != nil) != test.fails {
+					t.Errorf("Expected failure %v, received %v", test.fails, r)
+				}
+			}()
+
+			SimulateOffersHandler(writer, simulationSvc, shell_io_svc.NewShellReader(strings.NewReader(test.batch)))
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"github.com/lakshmaji/delivery-shell/clients"
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/msg_utils"
+)
+
+// Validates offers file using the loader, reports every schema violation
+func ValidateOffersHandler(writer clients.BaseWriter, loader func(string) ([]models.Offer, error), filename string) {
+	if _, err := loader(filename); err != nil {
+		writer.Write(msg_utils.MsgInvalidOffers)
+		writer.WriteError(err)
+	}
+	writer.Write(msg_utils.MsgValidOffers)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/clients"
+	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
+)
+
+func TestValidateOffersHandler(t *testing.T) {
+	tt := []struct {
+		description string
+		offersFile  string
+		expected    string
+		fails       bool
+	}{
+		{
+			description: "with valid offers",
+			offersFile:  "../offers.json",
+			expected:    "Valid configuration\n",
+		},
+		{
+			description: "with unknown operator",
+			offersFile:  "../utils/offer_utils/testdata/unknown_operator.json",
+			expected:    "Invalid configuration\n$[0].conditions[0].operator (line 8, column 29): unknown operator \"greaterThanOrEqualTo\", expected one of lessThan, greaterThanOrEqual, lessThanOrEqual, greaterThan, equal, notEqual, between, in",
+			fails:       true,
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.description, func(t *testing.T) {
+			var output bytes.Buffer
+			writer := clients.NewShellWriter(&output, true)
+
+			defer func() {
+				r := recover()
+				if output.String() != test.expected {
+					t.Errorf("Expected %q, received %q", test.expected, output.String())
+				}
+				if (r != nil) != test.fails {
+					t.Errorf("Expected failure %v, received %v", test.fails, r)
+				}
+			}()
+
+			ValidateOffersHandler(writer, offer_utils.LoadOffers, test.offersFile)
+		})
+	}
+}
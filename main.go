@@ -1,17 +1,40 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/lakshmaji/delivery-shell/clients"
 	"github.com/lakshmaji/delivery-shell/handlers"
+	"github.com/lakshmaji/delivery-shell/models"
 	"github.com/lakshmaji/delivery-shell/services/delivery_svc"
+	"github.com/lakshmaji/delivery-shell/services/ledger_svc"
 	"github.com/lakshmaji/delivery-shell/services/offers_svc"
 	"github.com/lakshmaji/delivery-shell/services/shell_io_svc"
+	"github.com/lakshmaji/delivery-shell/services/simulation_svc"
+	"github.com/lakshmaji/delivery-shell/services/store_svc"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+	"github.com/lakshmaji/delivery-shell/utils/fleet_utils"
 	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
+	"github.com/lakshmaji/delivery-shell/utils/rate_card_utils"
 )
 
 func main() {
+	autoApply := flag.Bool("auto-apply", false, "apply the best offer to packages without a known offer code")
+	explain := flag.Bool("explain", false, "print why every offer code did or did not apply, under each package row")
+	strict := flag.Bool("strict", false, "fail on unknown offer codes, instead of reporting them")
+	weightResolution := flag.Float64("weight-resolution", float64(delivery_svc.DefaultWeightResolution), "smallest weight (kg) told apart by shipment planning, ex: 0.1")
+	strategyName := flag.String("strategy", delivery_svc.MaxLoad.Name(), "rule picking the packages of every shipment: max-load, most-packages, heaviest-first, nearest-first, min-latest-delivery")
+	fleetFile := flag.String("fleet", "", "vehicles of the fleet (json file), instead of reading them from the input")
+	manifest := flag.String("manifest", "", "print trips of every vehicle after the packages, in text or json")
+	manifestFile := flag.String("manifest-file", "", "write the manifest to the file instead of printing it (required for json)")
+	flag.Parse()
+
 	// production or development
 	appEnv := os.Getenv("APP_ENVIRONMENT")
 	if appEnv == "" {
@@ -21,9 +44,139 @@ func main() {
 	reader := shell_io_svc.NewShellReader(os.Stdin)
 	writer := clients.NewShellWriter(os.Stdout, appEnv == "development")
 
+	// Pricing, falls back to default rate card when rate_card.json is not available
+	rateCard, err := rate_card_utils.LoadRateCard("rate_card.json")
+	if errors.Is(err, fs.ErrNotExist) {
+		rateCard, err = models.DefaultRateCard(), nil
+	}
+	if err != nil {
+		writer.WriteError(err)
+	}
+
+	offersFile := os.Getenv("OFFERS_FILE")
+	if offersFile == "" {
+		offersFile = "offers.json"
+	}
+
+	// Sub commands, ex: validate-offers [file], lint-offers [file], simulate-offers batch current proposed
+	if flag.NArg() > 0 {
+		file := offersFile
+		if flag.NArg() > 1 {
+			file = flag.Arg(1)
+		}
+		switch flag.Arg(0) {
+		case "validate-offers":
+			handlers.ValidateOffersHandler(writer, offer_utils.LoadOffers, file)
+			return
+		case "lint-offers":
+			handlers.LintOffersHandler(writer, offer_utils.LoadOffersLenient, file)
+			return
+		case "simulate-offers":
+			if flag.NArg() != 4 {
+				writer.WriteError(error_utils.ErrSimulateOffersUsage)
+			}
+			batch, err := os.Open(flag.Arg(1))
+			if err != nil {
+				writer.WriteError(err)
+			}
+			defer batch.Close()
+			current, currentCodes, err := newSimulatedDeliveryService(flag.Arg(2), rateCard, *autoApply)
+			if err != nil {
+				writer.WriteError(err)
+			}
+			proposed, proposedCodes, err := newSimulatedDeliveryService(flag.Arg(3), rateCard, *autoApply)
+			if err != nil {
+				writer.WriteError(err)
+			}
+			simulationSvc := simulation_svc.NewSimulationServiceWithOptions(current, proposed, simulation_svc.Options{OfferCodes: append(currentCodes, proposedCodes...)})
+			handlers.SimulateOffersHandler(writer, simulationSvc, shell_io_svc.NewShellReader(batch))
+			return
+		}
+	}
+
 	// Deps (go-way)
-	offers_svc_with_data := offers_svc.NewOffersService(offer_utils.LoadOffers)
-	delivery_svc := delivery_svc.NewDeliveryService(offers_svc_with_data)
+	// Offer redemptions, kept across runs when a ledger file is given, otherwise for the run only
+	var ledger ledger_svc.RedemptionLedger
+	if ledgerFile := os.Getenv("OFFERS_LEDGER_FILE"); ledgerFile != "" {
+		ledger, err = ledger_svc.NewFileLedger(ledgerFile)
+		if err != nil {
+			writer.WriteError(err)
+		}
+	}
+
+	store, err := newOfferStore(offersFile)
+	if err != nil {
+		writer.WriteError(err)
+	}
+	strategy, err := delivery_svc.ShipmentStrategyByName(*strategyName)
+	if err != nil {
+		writer.WriteError(err)
+	}
+
+	offers_svc_with_data := offers_svc.NewOffersServiceWithStore(store, rateCard.RoundingMode(), offers_svc.Options{AutoApply: *autoApply, Strict: *strict, Ledger: ledger})
+	delivery_svc := delivery_svc.NewDeliveryServiceWithOptions(offers_svc_with_data, rateCard, delivery_svc.Options{WeightResolution: *weightResolution, Strategy: strategy})
+
+	// Reloads offers when offers file is modified (ex: 30s), for long running processes
+	if watchInterval := os.Getenv("OFFERS_WATCH_INTERVAL"); watchInterval != "" {
+		interval, err := time.ParseDuration(watchInterval)
+		if err != nil || interval <= 0 {
+			writer.WriteError(error_utils.ErrOffersWatchInterval)
+		}
+		offers_svc_with_data.Watch(context.Background(), interval, writer)
+	}
+
+	if *manifest != "" && *manifest != models.ManifestText && *manifest != models.ManifestJSON {
+		writer.WriteError(error_utils.ErrManifestFormat(*manifest))
+	}
+	// json manifest is kept apart from the package rows, so that it can be parsed
+	if *manifest == models.ManifestJSON && *manifestFile == "" {
+		writer.WriteError(error_utils.ErrManifestFile)
+	}
+
+	// Vehicles are read from the input, unless a fleet file is given
+	var fleet models.Fleet
+	if *fleetFile != "" {
+		fleet, err = fleet_utils.LoadFleet(*fleetFile)
+		if err != nil {
+			writer.WriteError(err)
+		}
+	}
+
+	handlers.PackageHandlerWithOptions(writer, delivery_svc, reader, handlers.PackageHandlerOptions{Explain: *explain, Fleet: fleet, Manifest: *manifest, ManifestFile: *manifestFile})
+}
+
+// Offer store chosen by OFFERS_STORE
+//
+//	file (default) - OFFERS_FILE
+//	sqlite - OFFERS_DB, path of the database (ex: offers.db)
+//	http - OFFERS_URL, endpoint serving the offers
+func newOfferStore(offersFile string) (store_svc.OfferStore, error) {
+	switch kind := os.Getenv("OFFERS_STORE"); kind {
+	case "", "file":
+		return store_svc.NewFileStore(offersFile, offer_utils.LoadOffers), nil
+	case "sqlite":
+		return store_svc.NewSQLiteStore(os.Getenv("OFFERS_DB"))
+	case "http":
+		return store_svc.NewHTTPStore(os.Getenv("OFFERS_URL"), &http.Client{Timeout: 10 * time.Second}), nil
+	default:
+		return nil, error_utils.ErrOfferStoreKind(kind)
+	}
+}
 
-	handlers.PackageHandler(writer, delivery_svc, reader)
+// Delivery service of the offers file being simulated, along with its offer codes
+// offers are validated upfront so that the failing file is reported
+func newSimulatedDeliveryService(offersFile string, rateCard models.RateCard, autoApply bool) (delivery_svc.DeliveryService, []models.OfferCode, error) {
+	offers, err := offer_utils.LoadOffers(offersFile)
+	if err != nil {
+		return nil, nil, error_utils.ErrOfferStore(offersFile, err)
+	}
+	codes := make([]models.OfferCode, 0, len(offers))
+	for _, offer := range offers {
+		codes = append(codes, offer.Code)
+	}
+	load := func(string) ([]models.Offer, error) {
+		return offers, nil
+	}
+	offersSvc := offers_svc.NewOffersServiceWithOptions(load, offersFile, rateCard.RoundingMode(), offers_svc.Options{AutoApply: autoApply})
+	return delivery_svc.NewDeliveryService(offersSvc, rateCard), codes, nil
 }
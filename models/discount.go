@@ -0,0 +1,72 @@
+package models
+
+import "fmt"
+
+type DiscountStatus string
+
+const (
+	DiscountApplied       DiscountStatus = "applied"
+	DiscountNotApplicable DiscountStatus = "notApplicable" // conditions are not satisfied (or no offer code)
+	DiscountUnknownCode   DiscountStatus = "unknownCode"   // no offer is defined for the code (ex: misspelled)
+	DiscountNotActive     DiscountStatus = "notActive"     // offer is not yet active or outside of its schedule
+	DiscountExpired       DiscountStatus = "expired"
+	DiscountNotCombined   DiscountStatus = "notCombined"  // applicable, but not combinable with the applied offers
+	DiscountLimitReached  DiscountStatus = "limitReached" // applicable, but the redemption limit of the offer is reached
+)
+
+// Outcome of applying an offer code to a package
+type DiscountResult struct {
+	Code   OfferCode
+	Amount Money
+	Status DiscountStatus
+	Reason string // why the discount is not applied
+	// closest known code, when the code is unknown (ex: OFR001 for OFR0O1)
+	Suggestion OfferCode
+	// offer picked for the package (highest discount), as it does not have a known offer code
+	AutoApplied bool
+}
+
+func (d DiscountResult) IsApplied() bool {
+	return d.Status == DiscountApplied
+}
+
+// Sum of the applied discounts
+func TotalDiscount(results []DiscountResult) Money {
+	var total Money
+	for _, result := range results {
+		if result.IsApplied() {
+			total = total.Add(result.Amount)
+		}
+	}
+	return total
+}
+
+// Applied discounts only
+func AppliedDiscounts(results []DiscountResult) []DiscountResult {
+	applied := []DiscountResult{}
+	for _, result := range results {
+		if result.IsApplied() {
+			applied = append(applied, result)
+		}
+	}
+	return applied
+}
+
+// Results of the codes which are not known to the offers
+func UnknownCodes(results []DiscountResult) []DiscountResult {
+	unknown := []DiscountResult{}
+	for _, result := range results {
+		if result.Status == DiscountUnknownCode {
+			unknown = append(unknown, result)
+		}
+	}
+	return unknown
+}
+
+// Readable form of an unknown code, ex: OFR0O1 unknown code, did you mean OFR001?
+func (d DiscountResult) UnknownCodeWarning() string {
+	if d.Suggestion == "" {
+		return fmt.Sprintf("%s unknown code", d.Code)
+	}
+	return fmt.Sprintf("%s unknown code, did you mean %s?", d.Code, d.Suggestion)
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTotalDiscount(t *testing.T) {
+	results := []DiscountResult{
+		{Code: "OFR001", Amount: 1000, Status: DiscountApplied},
+		{Code: "OFR002", Status: DiscountNotApplicable},
+		{Code: "OFR003", Amount: 250, Status: DiscountApplied},
+	}
+	if total := TotalDiscount(results); total != 1250 {
+		t.Errorf("expected 1250, received %v", total)
+	}
+	if total := TotalDiscount(nil); total != 0 {
+		t.Errorf("expected 0, received %v", total)
+	}
+	expected := []DiscountResult{results[0], results[2]}
+	if applied := AppliedDiscounts(results); !reflect.DeepEqual(applied, expected) {
+		t.Errorf("expected %v, received %v", expected, applied)
+	}
+}
+
+func TestStackingDefaults(t *testing.T) {
+	if (Offer{}).StackingRule() != StackingExclusive || (Offer{}).Mode() != StackAdditive {
+		t.Error("offers should be exclusive and additive by default")
+	}
+	if !IsValidStacking(StackingBestOf) || IsValidStacking("all") {
+		t.Error("only exclusive, stackable and bestOf are valid")
+	}
+	if !IsValidStackMode(StackSequential) || IsValidStackMode("compound") {
+		t.Error("only additive and sequential are valid")
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Outcome of a single offer condition for a package
+type ConditionResult struct {
+	Rule     string // position within the condition tree (ex: any[1]), empty for flat conditions
+	Fact     string
+	Operator string
+	Expected string // compared value(s), ex: 200, 10 and 150 (exclusive)
+	Actual   string // value of the fact for the package
+	Pass     bool
+}
+
+func (c ConditionResult) String() string {
+	outcome := "fail"
+	if c.Pass {
+		outcome = "pass"
+	}
+	rule := ""
+	if c.Rule != "" {
+		rule = c.Rule + " "
+	}
+	return fmt.Sprintf("%s %s%s %s %s (actual %s)", outcome, rule, c.Fact, c.Operator, c.Expected, c.Actual)
+}
+
+// Why an offer code did or did not apply to a package
+type OfferExplanation struct {
+	Code       OfferCode
+	Found      bool           // offer is defined for the code
+	Status     DiscountStatus // applied, notApplicable, notActive, expired or unknownCode (combination with other offers is not considered)
+	Reason     string         // why the offer is not applicable
+	Suggestion OfferCode      // closest known code, when the code is unknown
+	Conditions []ConditionResult
+}
+
+func (e OfferExplanation) IsApplicable() bool {
+	return e.Status == DiscountApplied
+}
+
+// Readable form, offer outcome followed by the outcome of every condition (one per line)
+func (e OfferExplanation) String() string {
+	var lines []string
+	switch {
+	case e.Status == DiscountUnknownCode:
+		lines = append(lines, DiscountResult{Code: e.Code, Suggestion: e.Suggestion}.UnknownCodeWarning())
+	case e.IsApplicable():
+		lines = append(lines, fmt.Sprintf("%s applicable", e.Code))
+	default:
+		lines = append(lines, fmt.Sprintf("%s %s: %s", e.Code, e.Status, e.Reason))
+	}
+	for _, condition := range e.Conditions {
+		lines = append(lines, "  "+condition.String())
+	}
+	return strings.Join(lines, "\n")
+}
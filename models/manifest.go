@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lakshmaji/delivery-shell/utils/msg_utils"
+)
+
+// Output formats of the trip manifest
+const (
+	ManifestText = "text"
+	ManifestJSON = "json"
+)
+
+// Package carried on a trip
+type TripPackage struct {
+	Id          PackageID `json:"id"`
+	Weight      Weight    `json:"weight"`
+	Distance    Distance  `json:"distance"`
+	DeliveredAt float64   `json:"deliveredAt"` // hrs, est delivery time
+}
+
+// Trip of a vehicle, out to the furthest package and back
+type Trip struct {
+	Departure        float64       `json:"departure"` // hrs
+	Packages         []TripPackage `json:"packages"`  // heaviest package first
+	Load             Weight        `json:"load"`      // kg
+	FurthestDistance Distance      `json:"furthestDistance"`
+	Return           float64       `json:"return"` // hrs, when the vehicle is back
+}
+
+// Trips of a vehicle, in the order of departure
+type VehicleTrips struct {
+	Vehicle Vehicle `json:"vehicle"`
+	Trips   []Trip  `json:"trips"`
+}
+
+// Trips of every vehicle of the fleet, in the order of the fleet
+type Manifest []VehicleTrips
+
+// Est delivery time of every shipped package
+func (m Manifest) DeliveryTimes() PackageDeliveryTime {
+	deliveryTimes := make(PackageDeliveryTime)
+	for _, vehicle := range m {
+		for _, trip := range vehicle.Trips {
+			for _, pkg := range trip.Packages {
+				deliveryTimes[pkg.Id] = pkg.DeliveredAt
+			}
+		}
+	}
+	return deliveryTimes
+}
+
+// Convert Manifest to string, one row per trip (vehicles without trips are left out)
+func (m Manifest) FmtOutput() string {
+	finalStr := msg_utils.MsgManifestHeader + "\n"
+	for _, vehicle := range m {
+		label := string(vehicle.Vehicle.Id)
+		if vehicle.Vehicle.Type != "" {
+			label += fmt.Sprintf(" (%s)", vehicle.Vehicle.Type)
+		}
+		for i, trip := range vehicle.Trips {
+			ids := make([]string, len(trip.Packages))
+			for j, pkg := range trip.Packages {
+				ids[j] = string(pkg.Id)
+			}
+			finalStr += fmt.Sprintf("%s, %d, %.2f, %s, %.2f, %.2f, %.2f\n", label, i+1, trip.Departure, strings.Join(ids, " "), trip.Load, trip.FurthestDistance, trip.Return)
+		}
+	}
+	return finalStr
+}
+
+// Convert Manifest to (indented) json, every vehicle is listed along with its trips
+func (m Manifest) FmtJSON() (string, error) {
+	// vehicles without trips have empty trips, instead of null
+	vehicles := make(Manifest, len(m))
+	for i, vehicle := range m {
+		if vehicle.Trips == nil {
+			vehicle.Trips = []Trip{}
+		}
+		vehicles[i] = vehicle
+	}
+	content, err := json.MarshalIndent(vehicles, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(content) + "\n", nil
+}
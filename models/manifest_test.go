@@ -0,0 +1,84 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func sampleManifest() Manifest {
+	return Manifest{
+		{Vehicle: Vehicle{Id: "V1", Capacity: 200, Speed: 70}, Trips: []Trip{
+			{Departure: 0, Packages: []TripPackage{{Id: "PKG2", Weight: 75, Distance: 125, DeliveredAt: 1.78}, {Id: "PKG4", Weight: 110, Distance: 60, DeliveredAt: 0.85}}, Load: 185, FurthestDistance: 125, Return: 3.56},
+			{Departure: 3.56, Packages: []TripPackage{{Id: "PKG5", Weight: 155, Distance: 95, DeliveredAt: 4.91}}, Load: 155, FurthestDistance: 95, Return: 6.26},
+		}},
+		{Vehicle: Vehicle{Id: "TRUCK1", Type: "truck", Capacity: 300, Speed: 50, AvailableAt: 1}, Trips: []Trip{
+			{Departure: 1, Packages: []TripPackage{{Id: "PKG3", Weight: 175, Distance: 100, DeliveredAt: 3}}, Load: 175, FurthestDistance: 100, Return: 5},
+		}},
+		{Vehicle: Vehicle{Id: "BIKE1", Type: "bike", Capacity: 20, Speed: 40}},
+	}
+}
+
+func TestManifestDeliveryTimes(t *testing.T) {
+	expected := PackageDeliveryTime{"PKG2": 1.78, "PKG3": 3, "PKG4": 0.85, "PKG5": 4.91}
+	if result := sampleManifest().DeliveryTimes(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v received %v", expected, result)
+	}
+}
+
+func TestManifestOutput(t *testing.T) {
+	expected := "Vehicle, Trip, Departure, Packages, Load, Furthest Distance, Return\n" +
+		"V1, 1, 0.00, PKG2 PKG4, 185.00, 125.00, 3.56\n" +
+		"V1, 2, 3.56, PKG5, 155.00, 95.00, 6.26\n" +
+		"TRUCK1 (truck), 1, 1.00, PKG3, 175.00, 100.00, 5.00\n"
+	if result := sampleManifest().FmtOutput(); result != expected {
+		t.Errorf("expected\n%s\nreceived\n%s", expected, result)
+	}
+}
+
+func TestManifestJSON(t *testing.T) {
+	result, err := Manifest{sampleManifest()[1], sampleManifest()[2]}.FmtJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[
+  {
+    "vehicle": {
+      "id": "TRUCK1",
+      "type": "truck",
+      "capacity": 300,
+      "speed": 50,
+      "availableAt": 1
+    },
+    "trips": [
+      {
+        "departure": 1,
+        "packages": [
+          {
+            "id": "PKG3",
+            "weight": 175,
+            "distance": 100,
+            "deliveredAt": 3
+          }
+        ],
+        "load": 175,
+        "furthestDistance": 100,
+        "return": 5
+      }
+    ]
+  },
+  {
+    "vehicle": {
+      "id": "BIKE1",
+      "type": "bike",
+      "capacity": 20,
+      "speed": 40,
+      "availableAt": 0
+    },
+    "trips": []
+  }
+]
+`
+	if result != expected {
+		t.Errorf("expected\n%s\nreceived\n%s", expected, result)
+	}
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "halfUp"   // half away from zero
+	RoundHalfEven RoundingMode = "halfEven" // bankers rounding
+	RoundTruncate RoundingMode = "truncate" // towards zero
+)
+
+var RoundingModes = []RoundingMode{RoundHalfUp, RoundHalfEven, RoundTruncate}
+
+// No of minor units (cents) in a major unit
+const MoneyScale = 100
+
+// Fixed point amount in minor units (cents), so that costs, discounts and totals add up exactly
+type Money int64
+
+// Converts the given amount to money, rounding to the nearest minor unit using the given mode
+func NewMoney(amount float64, mode RoundingMode) Money {
+	return MoneyOf(amount, 1, mode)
+}
+
+// Money worth of quantity * rate (ex: weight * per kg rate), rounded to the nearest minor unit.
+//
+// The product is computed on the decimal representation of the values, hence 0.07 * 1475 is exactly 103.25
+// Non-finite values (NaN, ±Inf) are worth nothing
+func MoneyOf(quantity float64, rate float64, mode RoundingMode) Money {
+	if !isFinite(quantity) || !isFinite(rate) {
+		return 0
+	}
+	product := new(big.Rat).Mul(decimalRat(quantity), decimalRat(rate))
+	return Money(roundRat(product.Mul(product, big.NewRat(MoneyScale, 1)), mode))
+}
+
+// Portion of the amount (ex: discount of 7%), rounded to the nearest minor unit, non-finite ratio (NaN, ±Inf) is no portion
+func (m Money) MulRatio(ratio float64, mode RoundingMode) Money {
+	if !isFinite(ratio) {
+		return 0
+	}
+	product := new(big.Rat).Mul(big.NewRat(int64(m), 1), decimalRat(ratio))
+	return Money(roundRat(product, mode))
+}
+
+func (m Money) Add(other Money) Money {
+	return m + other
+}
+
+func (m Money) Sub(other Money) Money {
+	return m - other
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / MoneyScale
+}
+
+// Formats money with two decimals (ex: 175.00)
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/MoneyScale, value%MoneyScale)
+}
+
+func IsValidRoundingMode(mode RoundingMode) bool {
+	for _, m := range RoundingModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// Shortest decimal representation of the float, so that 0.07 is 7/100 (and not its binary approximation)
+func decimalRat(value float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	return r
+}
+
+func roundRat(r *big.Rat, mode RoundingMode) int64 {
+	quotient, remainder := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if remainder.Sign() == 0 || mode == RoundTruncate {
+		return quotient.Int64()
+	}
+
+	// compare the discarded fraction with half
+	twiceRemainder := new(big.Int).Abs(remainder)
+	twiceRemainder.Lsh(twiceRemainder, 1)
+	cmp := twiceRemainder.Cmp(r.Denom())
+
+	awayFromZero := cmp > 0
+	if cmp == 0 {
+		awayFromZero = mode == RoundHalfUp || quotient.Bit(0) == 1
+	}
+	if awayFromZero {
+		quotient.Add(quotient, big.NewInt(int64(r.Sign())))
+	}
+	return quotient.Int64()
+}
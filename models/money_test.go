@@ -0,0 +1,123 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewMoney(t *testing.T) {
+	tt := []struct {
+		name     string
+		amount   float64
+		mode     RoundingMode
+		expected Money
+	}{
+		{name: "whole amount", amount: 175, mode: RoundHalfUp, expected: 17500},
+		{name: "half up", amount: 1.005, mode: RoundHalfUp, expected: 101},
+		{name: "half up (below half)", amount: 1.004, mode: RoundHalfUp, expected: 100},
+		{name: "half up (negative)", amount: -1.005, mode: RoundHalfUp, expected: -101},
+		{name: "half even (rounds down to even)", amount: 1.005, mode: RoundHalfEven, expected: 100},
+		{name: "half even (rounds up to even)", amount: 1.015, mode: RoundHalfEven, expected: 102},
+		{name: "half even (above half)", amount: 1.0051, mode: RoundHalfEven, expected: 101},
+		{name: "truncate", amount: 1.009, mode: RoundTruncate, expected: 100},
+		{name: "truncate (negative)", amount: -1.009, mode: RoundTruncate, expected: -100},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			output := NewMoney(test.amount, test.mode)
+			if output != test.expected {
+				t.Errorf("should be %d received %d", test.expected, output)
+			}
+		})
+	}
+}
+
+func TestMoneyOf(t *testing.T) {
+	// 0.1 * 3 is 0.30000000000000004 using float64
+	if output := MoneyOf(0.1, 3, RoundTruncate); output != 30 {
+		t.Errorf("should be %d received %d", 30, output)
+	}
+	if output := MoneyOf(12.5, 8, RoundHalfUp); output != 10000 {
+		t.Errorf("should be %d received %d", 10000, output)
+	}
+	// non-finite values are worth nothing
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if output := MoneyOf(value, 10, RoundHalfUp); output != 0 {
+			t.Errorf("MoneyOf(%v) should be 0 received %d", value, output)
+		}
+		if output := Money(17500).MulRatio(value, RoundHalfUp); output != 0 {
+			t.Errorf("MulRatio(%v) should be 0 received %d", value, output)
+		}
+	}
+}
+
+func TestMulRatio(t *testing.T) {
+	tt := []struct {
+		name     string
+		amount   Money
+		ratio    float64
+		mode     RoundingMode
+		expected Money
+	}{
+		{name: "exact", amount: 150000, ratio: 0.07, mode: RoundHalfUp, expected: 10500},
+		{name: "exact decimal product", amount: 147500, ratio: 0.07, mode: RoundTruncate, expected: 10325},
+		{name: "half up", amount: 12345, ratio: 0.1, mode: RoundHalfUp, expected: 1235},
+		{name: "half even", amount: 12345, ratio: 0.1, mode: RoundHalfEven, expected: 1234},
+		{name: "truncate", amount: 12349, ratio: 0.1, mode: RoundTruncate, expected: 1234},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			output := test.amount.MulRatio(test.ratio, test.mode)
+			if output != test.expected {
+				t.Errorf("should be %d received %d", test.expected, output)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tt := []struct {
+		amount   Money
+		expected string
+	}{
+		{amount: 17500, expected: "175.00"},
+		{amount: 3505, expected: "35.05"},
+		{amount: 5, expected: "0.05"},
+		{amount: 0, expected: "0.00"},
+		{amount: -105, expected: "-1.05"},
+	}
+
+	for _, test := range tt {
+		t.Run(test.expected, func(t *testing.T) {
+			if output := test.amount.String(); output != test.expected {
+				t.Errorf("should be %s received %s", test.expected, output)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	amount := Money(1000)
+	if output := amount.Add(1); output != 1001 {
+		t.Errorf("should be %d received %d", 1001, output)
+	}
+	if output := amount.Sub(1); output != 999 {
+		t.Errorf("should be %d received %d", 999, output)
+	}
+	if output := amount.Float64(); output != 10 {
+		t.Errorf("should be %f received %f", 10.0, output)
+	}
+}
+
+func TestIsValidRoundingMode(t *testing.T) {
+	for _, mode := range RoundingModes {
+		if !IsValidRoundingMode(mode) {
+			t.Errorf("%s should be valid", mode)
+		}
+	}
+	if IsValidRoundingMode("ceil") {
+		t.Error("ceil should not be valid")
+	}
+}
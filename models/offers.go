@@ -1,30 +1,247 @@
 package models
 
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
 const (
 	LessThan           = "lessThan"
 	GreaterThanOrEqual = "greaterThanOrEqual"
 	LessThanOrEqual    = "lessThanOrEqual"
+	GreaterThan        = "greaterThan"
+	Equal              = "equal"
+	NotEqual           = "notEqual"
+	Between            = "between" // values: [lower bound, upper bound]
+	In                 = "in"      // values: set of values
 )
 
+// Facts available to offer conditions (built in), more facts can be registered with the offer engine
+const (
+	FactWeight           = "weight"
+	FactDistance         = "distance"
+	FactBaseDeliveryCost = "baseDeliveryCost"
+	FactDeliveryCost     = "deliveryCost"
+	FactPackageCount     = "packageCount"    // no of packages in the batch
+	FactEstDeliveryTime  = "estDeliveryTime" // 0 when est delivery time is not computed
+	FactCodePrefix       = "codePrefix"      // leading letters of the offer code (OFR of OFR001)
+)
+
+// Operators supported by the offer engine
+var Operators = []string{LessThan, GreaterThanOrEqual, LessThanOrEqual, GreaterThan, Equal, NotEqual, Between, In}
+
 type Condition struct {
-	Fact     string  `json:"fact"`     // distance weight
-	Operator string  `json:"operator"` // lessThan greaterThanOrEqual lessThanOrEqual
-	Value    float64 `json:"value"`
+	Fact      string    `json:"fact"`                // distance weight baseDeliveryCost deliveryCost packageCount estDeliveryTime codePrefix
+	Operator  string    `json:"operator"`            // lessThan greaterThanOrEqual lessThanOrEqual greaterThan equal notEqual between in
+	Value     float64   `json:"value"`               // compared value, not used by between and in
+	Text      string    `json:"text,omitempty"`      // compared value of text facts (equal, notEqual)
+	Values    []float64 `json:"values,omitempty"`    // bounds (between) or set of values (in)
+	Exclusive bool      `json:"exclusive,omitempty"` // between excludes both bounds, bounds are inclusive by default
 }
 
+// Readable form of the condition, ex: distance lessThan 200, weight between 10 and 150
+func (c Condition) String() string {
+	return fmt.Sprintf("%s %s %s", c.Fact, c.Operator, c.Expected())
+}
+
+// Compared value(s) of the condition, ex: 200, 10 and 150 (exclusive), [50 100]
+func (c Condition) Expected() string {
+	switch {
+	case c.Operator == Between && len(c.Values) == 2 && c.Exclusive:
+		return fmt.Sprintf("%v and %v (exclusive)", c.Values[0], c.Values[1])
+	case c.Operator == Between && len(c.Values) == 2:
+		return fmt.Sprintf("%v and %v", c.Values[0], c.Values[1])
+	case c.Operator == In:
+		return fmt.Sprintf("%v", c.Values)
+	case c.Text != "":
+		return fmt.Sprintf("%q", c.Text)
+	}
+	return fmt.Sprintf("%v", c.Value)
+}
+
+// Node of a condition tree, either a group (all, any, not) or a single condition
+//
+//	{"any": [{"fact": "distance", "operator": "lessThan", "value": 50}, {"not": {"fact": "weight", "operator": "lessThanOrEqual", "value": 200}}]}
+type ConditionNode struct {
+	All        []ConditionNode `json:"all,omitempty"` // satisfied when every node is satisfied
+	Any        []ConditionNode `json:"any,omitempty"` // satisfied when at least one node is satisfied
+	Not        *ConditionNode  `json:"not,omitempty"` // satisfied when the node is not satisfied
+	*Condition                 // leaf node
+}
+
+func (n ConditionNode) IsLeaf() bool {
+	return n.Condition != nil
+}
+
+// Recurring window in which an offer is active, evaluated in the local time of the evaluation clock
+//
+//	{"days": ["saturday", "sunday"], "from": "09:00", "until": "18:00"}
+type OfferSchedule struct {
+	Days  []string `json:"days,omitempty"`  // monday ... sunday, every day when empty
+	From  string   `json:"from,omitempty"`  // time of day (HH:MM) inclusive
+	Until string   `json:"until,omitempty"` // time of day (HH:MM) exclusive, window spans midnight when before from
+}
+
+type DiscountType string
+
+const (
+	DiscountPercentage DiscountType = "percentage" // fraction of delivery cost, ex: 0.1
+	DiscountFlat       DiscountType = "flat"       // fixed amount, ex: 50
+)
+
+var DiscountTypes = []DiscountType{DiscountPercentage, DiscountFlat}
+
+// How an offer combines with the other offers (codes) of a package
+type Stacking string
+
+const (
+	StackingExclusive Stacking = "exclusive" // applied alone, never combined with other offers
+	StackingStackable Stacking = "stackable" // combined with the other stackable offers and the best of bestOf offers
+	StackingBestOf    Stacking = "bestOf"    // only the highest discount among bestOf offers is applied
+)
+
+var StackingRules = []Stacking{StackingExclusive, StackingStackable, StackingBestOf}
+
+// Amount on which a stacked discount is computed
+type StackMode string
+
+const (
+	StackAdditive   StackMode = "additive"   // on the original delivery cost
+	StackSequential StackMode = "sequential" // on the amount remaining after the previously applied offers
+)
+
+var StackModes = []StackMode{StackAdditive, StackSequential}
+
 type Offer struct {
-	Code       OfferCode
-	Conditions []Condition
-	Discount   float64
+	Code         OfferCode
+	Conditions   []Condition    // all of them should be satisfied
+	Rules        *ConditionNode // condition tree, should be satisfied along with conditions
+	Discount     float64        // fraction (percentage) or amount (flat)
+	DiscountType DiscountType   // percentage (default) or flat
+	MaxDiscount  *float64       // percentage discount is capped at this amount
+	MinDiscount  *float64       // percentage discount is at least this amount
+	ValidFrom    *time.Time     // active from (inclusive), RFC 3339
+	ValidUntil   *time.Time     // expires at (exclusive), RFC 3339
+	Schedule     *OfferSchedule // recurring window, along with validity period
+	Stacking     Stacking       // exclusive (default), stackable or bestOf
+	Priority     int            // stacked offers are applied in ascending order of priority
+	StackMode    StackMode      // additive (default) or sequential
+	AutoApply    *bool          // considered by auto apply (default), false opts out
+	// redemption limits, 0 is unlimited
+	MaxRedemptions int // in total, ex: first 500 redemptions only
+	MaxPerCustomer int // per customer, packages without customer are not eligible
+}
+
+// Discount type, percentage when not specified
+func (o Offer) Type() DiscountType {
+	if o.DiscountType == "" {
+		return DiscountPercentage
+	}
+	return o.DiscountType
+}
+
+// Considered when picking an offer for packages without a known code, unless opted out
+func (o Offer) IsAutoApplicable() bool {
+	return o.AutoApply == nil || *o.AutoApply
+}
+
+// Stacking rule, exclusive when not specified
+func (o Offer) StackingRule() Stacking {
+	if o.Stacking == "" {
+		return StackingExclusive
+	}
+	return o.Stacking
+}
+
+// Stack mode, additive when not specified
+func (o Offer) Mode() StackMode {
+	if o.StackMode == "" {
+		return StackAdditive
+	}
+	return o.StackMode
+}
+
+func IsValidStacking(stacking Stacking) bool {
+	for _, s := range StackingRules {
+		if s == stacking {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidStackMode(mode StackMode) bool {
+	for _, m := range StackModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidDiscountType(discountType DiscountType) bool {
+	for _, t := range DiscountTypes {
+		if t == discountType {
+			return true
+		}
+	}
+	return false
+}
+
+// Flat conditions and rules combined into a single condition tree
+func (o Offer) ConditionTree() ConditionNode {
+	root := ConditionNode{All: make([]ConditionNode, 0, len(o.Conditions)+1)}
+	for i := range o.Conditions {
+		root.All = append(root.All, ConditionNode{Condition: &o.Conditions[i]})
+	}
+	if o.Rules != nil {
+		root.All = append(root.All, *o.Rules)
+	}
+	return root
 }
 
 type Facts []string
 
+// Facts referenced by the offer conditions and rules (sorted, without duplicates)
 func (o Offer) FactsToValidate() Facts {
-	return []string{"distance", "weight"}
+	seen := make(map[string]bool)
+	facts := Facts{}
+	var walk func(node ConditionNode)
+	walk = func(node ConditionNode) {
+		if node.IsLeaf() && !seen[node.Fact] {
+			seen[node.Fact] = true
+			facts = append(facts, node.Fact)
+		}
+		if node.Not != nil {
+			walk(*node.Not)
+		}
+		for _, child := range append(node.All, node.Any...) {
+			walk(child)
+		}
+	}
+	walk(o.ConditionTree())
+	sort.Strings(facts)
+	return facts
 }
 
+// Values of a package (and its batch) against which the offer conditions are evaluated
 type Fact struct {
-	Distance Distance
-	Weight   Weight
+	Distance         Distance
+	Weight           Weight
+	BaseDeliveryCost float64
+	DeliveryCost     float64
+	PackageCount     int
+	EstDeliveryTime  float64
+	Code             OfferCode
+	Customer         CustomerID // not a condition fact, used for redemption limits
+}
+
+func IsKnownOperator(operator string) bool {
+	for _, op := range Operators {
+		if op == operator {
+			return true
+		}
+	}
+	return false
 }
@@ -6,15 +6,93 @@ import (
 )
 
 func TestFactsToValidate(t *testing.T) {
-	offer := Offer{}
+	offer := Offer{
+		Conditions: []Condition{
+			{Fact: "weight", Operator: LessThan, Value: 10},
+			{Fact: "distance", Operator: LessThan, Value: 10},
+		},
+		Rules: &ConditionNode{Any: []ConditionNode{
+			{Condition: &Condition{Fact: "weight", Operator: GreaterThan, Value: 5}},
+			{Not: &ConditionNode{Condition: &Condition{Fact: "packageCount", Operator: Equal, Value: 1}}},
+		}},
+	}
 
 	result := offer.FactsToValidate()
-	expected := Facts{"distance", "weight"}
+	expected := Facts{"distance", "packageCount", "weight"}
 
-	if len(result) != 2 {
-		t.Error("There should be only two facts")
+	if len(result) != 3 {
+		t.Error("There should be only three facts")
 	}
 	if !reflect.DeepEqual(result, expected) {
 		t.Error("facts are invalidated or modified, verify if this is intentional")
 	}
+
+	if result := (Offer{}).FactsToValidate(); len(result) != 0 {
+		t.Errorf("offer without conditions should not have facts, received %v", result)
+	}
+}
+
+func TestIsKnownOperator(t *testing.T) {
+	for _, operator := range Operators {
+		if !IsKnownOperator(operator) {
+			t.Errorf("%s should be a known operator", operator)
+		}
+	}
+	if IsKnownOperator("greaterThanOrEqualTo") {
+		t.Error("greaterThanOrEqualTo should not be a known operator")
+	}
+}
+
+func TestConditionTree(t *testing.T) {
+	rules := &ConditionNode{Any: []ConditionNode{
+		{Condition: &Condition{Fact: "distance", Operator: LessThan, Value: 50}},
+		{Condition: &Condition{Fact: "weight", Operator: GreaterThan, Value: 200}},
+	}}
+	offer := Offer{
+		Code:       "A",
+		Conditions: []Condition{{Fact: "distance", Operator: GreaterThan, Value: 10}},
+		Rules:      rules,
+	}
+
+	expected := ConditionNode{All: []ConditionNode{
+		{Condition: &Condition{Fact: "distance", Operator: GreaterThan, Value: 10}},
+		*rules,
+	}}
+	if result := offer.ConditionTree(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v received %v", expected, result)
+	}
+
+	if result := (Offer{}).ConditionTree(); len(result.All) != 0 || result.IsLeaf() {
+		t.Errorf("expected empty tree received %v", result)
+	}
+}
+
+func TestConditionString(t *testing.T) {
+	tt := []struct {
+		condition Condition
+		expected  string
+	}{
+		{Condition{Fact: "distance", Operator: LessThan, Value: 200}, "distance lessThan 200"},
+		{Condition{Fact: "weight", Operator: Between, Values: []float64{10, 150.5}}, "weight between 10 and 150.5"},
+		{Condition{Fact: "weight", Operator: Between, Values: []float64{10, 150}, Exclusive: true}, "weight between 10 and 150 (exclusive)"},
+		{Condition{Fact: "distance", Operator: In, Values: []float64{50, 100}}, "distance in [50 100]"},
+		{Condition{Fact: "codePrefix", Operator: Equal, Text: "OFR"}, `codePrefix equal "OFR"`},
+	}
+	for _, test := range tt {
+		if result := test.condition.String(); result != test.expected {
+			t.Errorf("expected %s, received %s", test.expected, result)
+		}
+	}
+}
+
+func TestOfferType(t *testing.T) {
+	if (Offer{}).Type() != DiscountPercentage {
+		t.Error("offers should be percentage discounts by default")
+	}
+	if (Offer{DiscountType: DiscountFlat}).Type() != DiscountFlat {
+		t.Error("discount type should not be changed")
+	}
+	if !IsValidDiscountType(DiscountFlat) || IsValidDiscountType("bogo") {
+		t.Error("only percentage and flat discount types are valid")
+	}
 }
@@ -1,7 +1,16 @@
 package models
 
+import "strings"
+
 type OfferCode string
+
+// Placeholder for packages without an offer code
+const NoOfferCode OfferCode = "NA"
+
 type PackageID string
+
+// Identifies the customer of a package, per customer offer limits are tracked with it
+type CustomerID string
 type Weight = float64
 type Distance = float64
 
@@ -9,18 +18,46 @@ type PackageDetails struct {
 	Id          PackageID
 	Weight      Weight
 	Distance    Distance
-	Code        OfferCode // offer code which is applied on this package
+	Code        OfferCode   // offer code which is applied on this package (first of the codes)
+	Codes       []OfferCode // offer codes which are applied on this package
+	Customer    CustomerID  // optional
 	DeliveredIn float64
 }
 
 type BaseDeliveryCost float64
 
+// Offer codes of the package, falls back to the single code
+func (p *PackageDetails) OfferCodes() []OfferCode {
+	if len(p.Codes) > 0 {
+		return p.Codes
+	}
+	if p.Code == "" {
+		return nil
+	}
+	return []OfferCode{p.Code}
+}
+
+// Parses comma separated offer codes (ex: OFR001,OFR003), ignoring empty and repeated codes
+func ParseOfferCodes(input string) []OfferCode {
+	codes := []OfferCode{}
+	seen := make(map[OfferCode]bool)
+	for _, value := range strings.Split(input, ",") {
+		code := OfferCode(strings.TrimSpace(value))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes
+}
+
 func (p *PackageDetails) IsSamePackage(box PackageDetails) bool {
 	return p.Id == box.Id
 }
 
 func (p *PackageDetails) IsValid() bool {
-	if p.Weight <= 0 || p.Distance <= 0 || p.Id == "" {
+	if p.Weight <= 0 || p.Distance <= 0 || p.Id == "" || !isFinite(p.Weight) || !isFinite(p.Distance) {
 		return false
 	}
 	return true
@@ -1,6 +1,10 @@
 package models
 
-import "testing"
+import (
+	"math"
+	"reflect"
+	"testing"
+)
 
 func TestIsValid(t *testing.T) {
 
@@ -36,6 +40,24 @@ func TestIsValid(t *testing.T) {
 				Distance: 0,
 			},
 		},
+		{
+			name:     "weight is not a number",
+			expected: false,
+			input: PackageDetails{
+				Id:       "PKG1",
+				Weight:   math.NaN(),
+				Distance: 20,
+			},
+		},
+		{
+			name:     "infinite distance",
+			expected: false,
+			input: PackageDetails{
+				Id:       "PKG1",
+				Weight:   50,
+				Distance: math.Inf(1),
+			},
+		},
 	}
 
 	for _, test := range tt {
@@ -101,3 +123,41 @@ func TestIsSamePackage(t *testing.T) {
 		})
 	}
 }
+
+func TestOfferCodes(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    PackageDetails
+		expected []OfferCode
+	}{
+		{name: "without codes", input: PackageDetails{}, expected: nil},
+		{name: "single code", input: PackageDetails{Code: "OFR001"}, expected: []OfferCode{"OFR001"}},
+		{name: "several codes", input: PackageDetails{Code: "OFR001", Codes: []OfferCode{"OFR001", "OFR003"}}, expected: []OfferCode{"OFR001", "OFR003"}},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			output := test.input.OfferCodes()
+			if !reflect.DeepEqual(output, test.expected) {
+				t.Errorf("should be %v received %v", test.expected, output)
+			}
+		})
+	}
+}
+
+func TestParseOfferCodes(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected []OfferCode
+	}{
+		{input: "OFR001", expected: []OfferCode{"OFR001"}},
+		{input: "OFR001,OFR003", expected: []OfferCode{"OFR001", "OFR003"}},
+		{input: "OFR001,,OFR003,OFR001,", expected: []OfferCode{"OFR001", "OFR003"}},
+		{input: "", expected: []OfferCode{}},
+	}
+	for _, test := range tt {
+		output := ParseOfferCodes(test.input)
+		if !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("%q should be %v received %v", test.input, test.expected, output)
+		}
+	}
+}
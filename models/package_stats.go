@@ -2,15 +2,19 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/lakshmaji/delivery-shell/utils/msg_utils"
 )
 
 type PackageStats struct {
 	Id                PackageID
-	Discount          float64
-	TotalDeliveryCost float64
+	Discount          Money
+	TotalDeliveryCost Money
 	EstDeliveryTime   float64
+	Breakdown         []SlabCharge       // delivery cost per slab, for reconciliation
+	Offers            []DiscountResult   // outcome of every offer code (applied ones with amount), with reason when not applied
+	Explanations      []OfferExplanation // why every offer code did or did not apply (explain mode only)
 }
 
 type PackageStatsList []PackageStats
@@ -25,11 +29,30 @@ func (pList PackageStatsList) FmtOutput(computesDeliveryTime bool) string {
 	}
 	finalStr += "\n"
 	for _, pkg := range pList {
-		finalStr += fmt.Sprintf("%s, %.2f, %.2f", pkg.Id, pkg.Discount, pkg.TotalDeliveryCost)
+		finalStr += fmt.Sprintf("%s, %s, %s", pkg.Id, pkg.Discount, pkg.TotalDeliveryCost)
 		if computesDeliveryTime {
 			finalStr += fmt.Sprintf(", %.2f", pkg.EstDeliveryTime)
 		}
 		finalStr += "\n"
+		// discount of every stacked (or auto applied) offer
+		if applied := AppliedDiscounts(pkg.Offers); len(applied) > 1 || (len(applied) == 1 && applied[0].AutoApplied) {
+			for _, offer := range applied {
+				finalStr += fmt.Sprintf("  %s %s", offer.Code, offer.Amount)
+				if offer.AutoApplied {
+					finalStr += " (auto applied)"
+				}
+				finalStr += "\n"
+			}
+		}
+		// explanations report unknown codes as well
+		if len(pkg.Explanations) == 0 {
+			for _, unknown := range UnknownCodes(pkg.Offers) {
+				finalStr += "  " + unknown.UnknownCodeWarning() + "\n"
+			}
+		}
+		for _, explanation := range pkg.Explanations {
+			finalStr += "  " + strings.ReplaceAll(explanation.String(), "\n", "\n  ") + "\n"
+		}
 	}
 	return finalStr
 }
@@ -12,16 +12,66 @@ func TestMapPackageStatsOutput(t *testing.T) {
 	}{
 		{
 			description:    "TestMapPackageStatsOutput",
-			boxes:          PackageStatsList{PackageStats{Id: "PKG 1", Discount: 10, TotalDeliveryCost: 100}, PackageStats{Id: "PKG 10", Discount: 13, TotalDeliveryCost: 70}},
+			boxes:          PackageStatsList{PackageStats{Id: "PKG 1", Discount: 1000, TotalDeliveryCost: 10000}, PackageStats{Id: "PKG 10", Discount: 1300, TotalDeliveryCost: 7000}},
 			computeEstTime: false,
 			expected:       "Package Id, Discount, Total Delivery Cost\nPKG 1, 10.00, 100.00\nPKG 10, 13.00, 70.00\n",
 		},
 		{
 			description:    "TestMapPackageStatsOutput",
-			boxes:          PackageStatsList{PackageStats{Id: "PKG 1", Discount: 10, TotalDeliveryCost: 100, EstDeliveryTime: 0.43}, PackageStats{Id: "PKG 10", Discount: 13, TotalDeliveryCost: 70, EstDeliveryTime: 1.78}},
+			boxes:          PackageStatsList{PackageStats{Id: "PKG 1", Discount: 1000, TotalDeliveryCost: 10000, EstDeliveryTime: 0.43}, PackageStats{Id: "PKG 10", Discount: 1300, TotalDeliveryCost: 7000, EstDeliveryTime: 1.78}},
 			computeEstTime: true,
 			expected:       "Package Id, Discount, Total Delivery Cost, Total Est Time\nPKG 1, 10.00, 100.00, 0.43\nPKG 10, 13.00, 70.00, 1.78\n",
 		},
+		{
+			description: "TestMapPackageStatsOutput (stacked offers)",
+			boxes: PackageStatsList{
+				PackageStats{Id: "PKG1", Discount: 1500, TotalDeliveryCost: 8500, Offers: []DiscountResult{
+					{Code: "OFR001", Amount: 1000, Status: DiscountApplied},
+					{Code: "OFR002", Status: DiscountNotCombined},
+					{Code: "OFR003", Amount: 500, Status: DiscountApplied},
+				}},
+				PackageStats{Id: "PKG2", Discount: 1000, TotalDeliveryCost: 9000, Offers: []DiscountResult{{Code: "OFR001", Amount: 1000, Status: DiscountApplied}}},
+			},
+			computeEstTime: false,
+			expected:       "Package Id, Discount, Total Delivery Cost\nPKG1, 15.00, 85.00\n  OFR001 10.00\n  OFR003 5.00\nPKG2, 10.00, 90.00\n",
+		},
+		{
+			description: "TestMapPackageStatsOutput (auto applied offer)",
+			boxes: PackageStatsList{
+				PackageStats{Id: "PKG1", Discount: 3500, TotalDeliveryCost: 66500, Offers: []DiscountResult{
+					{Code: "NA", Status: DiscountNotApplicable},
+					{Code: "OFR003", Amount: 3500, Status: DiscountApplied, AutoApplied: true},
+				}},
+			},
+			computeEstTime: false,
+			expected:       "Package Id, Discount, Total Delivery Cost\nPKG1, 35.00, 665.00\n  OFR003 35.00 (auto applied)\n",
+		},
+		{
+			description: "TestMapPackageStatsOutput (unknown codes)",
+			boxes: PackageStatsList{
+				PackageStats{Id: "PKG1", Discount: 0, TotalDeliveryCost: 17500, Offers: []DiscountResult{
+					{Code: "OFR001", Status: DiscountNotApplicable, Reason: "conditions are not satisfied"},
+					{Code: "OFR0O3", Status: DiscountUnknownCode, Suggestion: "OFR003"},
+					{Code: "SUMMER", Status: DiscountUnknownCode},
+				}},
+			},
+			computeEstTime: false,
+			expected:       "Package Id, Discount, Total Delivery Cost\nPKG1, 0.00, 175.00\n  OFR0O3 unknown code, did you mean OFR003?\n  SUMMER unknown code\n",
+		},
+		{
+			description: "TestMapPackageStatsOutput (explain)",
+			boxes: PackageStatsList{
+				PackageStats{Id: "PKG1", Discount: 3500, TotalDeliveryCost: 66500, Explanations: []OfferExplanation{
+					{Code: "OFR003", Found: true, Status: DiscountApplied, Conditions: []ConditionResult{
+						{Fact: "distance", Operator: "between", Expected: "50 and 250", Actual: "100", Pass: true},
+						{Rule: "rules.not", Fact: "weight", Operator: "greaterThan", Expected: "150", Actual: "10", Pass: false},
+					}},
+					{Code: "OFR0O1", Status: DiscountUnknownCode, Reason: "no offer found for the code", Suggestion: "OFR001"},
+				}},
+			},
+			computeEstTime: false,
+			expected:       "Package Id, Discount, Total Delivery Cost\nPKG1, 35.00, 665.00\n  OFR003 applicable\n    pass distance between 50 and 250 (actual 100)\n    fail rules.not weight greaterThan 150 (actual 10)\n  OFR0O1 unknown code, did you mean OFR001?\n",
+		},
 	}
 
 	for _, tc := range tt {
@@ -0,0 +1,79 @@
+package models
+
+const (
+	// Each slab charges only the portion of the value falling within the slab
+	SlabModeCumulative = "cumulative"
+	// The slab containing the value charges the whole value
+	SlabModeFlat = "flat"
+)
+
+// A pricing band, starts at From (inclusive) and ends at To (exclusive)
+// To is omitted (zero) for the last slab, which is open ended
+type Slab struct {
+	From float64 `json:"from"`
+	To   float64 `json:"to,omitempty"`
+	Rate float64 `json:"rate"`
+}
+
+type SlabPricing struct {
+	Mode  string `json:"mode"` // cumulative flat
+	Slabs []Slab `json:"slabs"`
+}
+
+// Pricing applied on top of the base delivery cost
+// Slabs takes precedence over the linear (per kg, per km) rate of the same fact
+type RateCard struct {
+	Name          string       `json:"name"`
+	PerKg         float64      `json:"perKg"`
+	PerKm         float64      `json:"perKm"`
+	MinimumCharge float64      `json:"minimumCharge"`
+	WeightSlabs   *SlabPricing `json:"weightSlabs,omitempty"`
+	DistanceSlabs *SlabPricing `json:"distanceSlabs,omitempty"`
+	Rounding      RoundingMode `json:"rounding,omitempty"` // halfUp (default) halfEven truncate
+}
+
+// Amount charged by a single slab, so that finance can reconcile the delivery cost
+type SlabCharge struct {
+	Fact   string
+	From   float64
+	To     float64
+	Units  float64
+	Rate   float64
+	Amount Money
+}
+
+// Rate card matching the original pricing formulae
+//
+//	delivery cost = base delivery cost + (weight * 10) + (distance * 5)
+func DefaultRateCard() RateCard {
+	return RateCard{
+		Name:  "default",
+		PerKg: 10,
+		PerKm: 5,
+	}
+}
+
+// Rounding mode used for costs and discounts, defaults to half up
+func (r RateCard) RoundingMode() RoundingMode {
+	if r.Rounding == "" {
+		return RoundHalfUp
+	}
+	return r.Rounding
+}
+
+// Slabs used for pricing the given fact, linear rate is a single open ended slab
+func (r RateCard) SlabsFor(fact string) SlabPricing {
+	switch fact {
+	case FactWeight:
+		if r.WeightSlabs != nil {
+			return *r.WeightSlabs
+		}
+		return SlabPricing{Mode: SlabModeCumulative, Slabs: []Slab{{Rate: r.PerKg}}}
+	case FactDistance:
+		if r.DistanceSlabs != nil {
+			return *r.DistanceSlabs
+		}
+		return SlabPricing{Mode: SlabModeCumulative, Slabs: []Slab{{Rate: r.PerKm}}}
+	}
+	return SlabPricing{}
+}
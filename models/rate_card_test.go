@@ -0,0 +1,70 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultRateCard(t *testing.T) {
+	card := DefaultRateCard()
+	expected := RateCard{Name: "default", PerKg: 10, PerKm: 5, MinimumCharge: 0}
+
+	if card != expected {
+		t.Errorf("expected %v received %v, verify if this is intentional", expected, card)
+	}
+}
+
+func TestSlabsFor(t *testing.T) {
+	weightSlabs := &SlabPricing{Mode: SlabModeFlat, Slabs: []Slab{{From: 0, To: 5, Rate: 10}, {From: 5, Rate: 8}}}
+
+	tt := []struct {
+		desc     string
+		card     RateCard
+		fact     string
+		expected SlabPricing
+	}{
+		{
+			desc:     "linear weight rate",
+			card:     DefaultRateCard(),
+			fact:     FactWeight,
+			expected: SlabPricing{Mode: SlabModeCumulative, Slabs: []Slab{{Rate: 10}}},
+		},
+		{
+			desc:     "linear distance rate",
+			card:     DefaultRateCard(),
+			fact:     FactDistance,
+			expected: SlabPricing{Mode: SlabModeCumulative, Slabs: []Slab{{Rate: 5}}},
+		},
+		{
+			desc:     "weight slabs",
+			card:     RateCard{Name: "slabs", PerKg: 10, PerKm: 5, WeightSlabs: weightSlabs},
+			fact:     FactWeight,
+			expected: *weightSlabs,
+		},
+		{
+			desc:     "unknown fact",
+			card:     DefaultRateCard(),
+			fact:     "volume",
+			expected: SlabPricing{},
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result := test.card.SlabsFor(test.fact)
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %v received %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestRoundingMode(t *testing.T) {
+	if mode := DefaultRateCard().RoundingMode(); mode != RoundHalfUp {
+		t.Errorf("expected %s received %s", RoundHalfUp, mode)
+	}
+	card := RateCard{Name: "truncated", Rounding: RoundTruncate}
+	if mode := card.RoundingMode(); mode != RoundTruncate {
+		t.Errorf("expected %s received %s", RoundTruncate, mode)
+	}
+}
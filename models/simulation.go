@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/lakshmaji/delivery-shell/utils/msg_utils"
+)
+
+// Usage of an offer over a batch of packages
+type OfferUsage struct {
+	Packages int   // packages qualified for (granted) the discount
+	Discount Money // total discount given
+}
+
+// Add a granted discount
+func (u OfferUsage) Add(discount Money) OfferUsage {
+	return OfferUsage{Packages: u.Packages + 1, Discount: u.Discount.Add(discount)}
+}
+
+// Average discount per qualified package, 0 when no package qualified
+func (u OfferUsage) AverageDiscount() Money {
+	if u.Packages == 0 {
+		return 0
+	}
+	return Money(math.Round(float64(u.Discount) / float64(u.Packages)))
+}
+
+// Usage of an offer code under the current and the proposed offers
+type OfferComparison struct {
+	Code     OfferCode
+	Current  OfferUsage
+	Proposed OfferUsage
+}
+
+// Additional discount given by the proposed offers (negative when they give less)
+func (c OfferComparison) DiscountDifference() Money {
+	return c.Proposed.Discount.Sub(c.Current.Discount)
+}
+
+// What the proposed offers would cost compared to the current offers, over a batch of packages
+type OfferSimulation struct {
+	Packages int               // in the batch
+	Offers   []OfferComparison // every offer code known to either offers (even when no package carries it), sorted by code
+	// packages with any discount, and the discount of the batch
+	Current  OfferUsage
+	Proposed OfferUsage
+}
+
+// Simulation listing the offer codes of both offers, packages carrying other codes add them as they are added
+func NewOfferSimulation(codes []OfferCode) OfferSimulation {
+	simulation := OfferSimulation{Offers: []OfferComparison{}}
+	for _, code := range codes {
+		simulation.comparison(DiscountResult{Code: code})
+	}
+	sort.SliceStable(simulation.Offers, func(i, j int) bool {
+		return simulation.Offers[i].Code < simulation.Offers[j].Code
+	})
+	return simulation
+}
+
+// Adds the discounts of a package under the current and the proposed offers
+func (s *OfferSimulation) Add(current []DiscountResult, proposed []DiscountResult) {
+	s.Packages++
+	if applied := AppliedDiscounts(current); len(applied) > 0 {
+		s.Current = s.Current.Add(TotalDiscount(applied))
+	}
+	if applied := AppliedDiscounts(proposed); len(applied) > 0 {
+		s.Proposed = s.Proposed.Add(TotalDiscount(applied))
+	}
+	for _, result := range current {
+		if comparison := s.comparison(result); comparison != nil && result.IsApplied() {
+			comparison.Current = comparison.Current.Add(result.Amount)
+		}
+	}
+	for _, result := range proposed {
+		if comparison := s.comparison(result); comparison != nil && result.IsApplied() {
+			comparison.Proposed = comparison.Proposed.Add(result.Amount)
+		}
+	}
+	sort.SliceStable(s.Offers, func(i, j int) bool {
+		return s.Offers[i].Code < s.Offers[j].Code
+	})
+}
+
+// Comparison of the code, nil when the code does not refer to an offer (no offer code, unknown code)
+func (s *OfferSimulation) comparison(result DiscountResult) *OfferComparison {
+	if result.Code == "" || result.Code == NoOfferCode || result.Status == DiscountUnknownCode {
+		return nil
+	}
+	for i := range s.Offers {
+		if s.Offers[i].Code == result.Code {
+			return &s.Offers[i]
+		}
+	}
+	s.Offers = append(s.Offers, OfferComparison{Code: result.Code})
+	return &s.Offers[len(s.Offers)-1]
+}
+
+// Additional discount given by the proposed offers over the batch
+func (s OfferSimulation) DiscountDifference() Money {
+	return s.Proposed.Discount.Sub(s.Current.Discount)
+}
+
+// Convert OfferSimulation to string, one row per offer code followed by the totals of the batch
+func (s OfferSimulation) FmtOutput() string {
+	finalStr := msg_utils.MsgOfferSimulationHeader + "\n"
+	for _, offer := range s.Offers {
+		finalStr += fmtSimulationRow(string(offer.Code), offer.Current, offer.Proposed, offer.DiscountDifference())
+	}
+	finalStr += fmtSimulationRow(fmt.Sprintf(msg_utils.MsgOfferSimulationTotal, s.Packages), s.Current, s.Proposed, s.DiscountDifference())
+	return finalStr
+}
+
+func fmtSimulationRow(label string, current OfferUsage, proposed OfferUsage, difference Money) string {
+	sign := ""
+	if difference > 0 {
+		sign = "+"
+	}
+	return fmt.Sprintf("%s, %d, %s, %s, %d, %s, %s, %s%s\n", label, current.Packages, current.Discount, current.AverageDiscount(), proposed.Packages, proposed.Discount, proposed.AverageDiscount(), sign, difference)
+}
@@ -1,7 +1,39 @@
 package models
 
+import "fmt"
+
+type VehicleID string
+
 type Vehicle struct {
-	WaitTime float64
+	Id          VehicleID `json:"id"`
+	Type        string    `json:"type,omitempty"` // ex: bike, van, truck
+	Capacity    Weight    `json:"capacity"`       // kg
+	Speed       float64   `json:"speed"`          // km/hr
+	AvailableAt float64   `json:"availableAt"`    // hrs, when the vehicle is available for its first trip
+	WaitTime    float64   `json:"-"`              // hrs, when the vehicle is available for its next trip (while planning)
+}
+
+// Vehicles of the delivery fleet, in the order of preference when several are available at the same time
+type Fleet []Vehicle
+
+// Fleet of identical vehicles (V1, V2 ...), available from the start
+func NewFleet(noOfVehicles int, speed int, capacity Weight) Fleet {
+	fleet := make(Fleet, noOfVehicles)
+	for i := range fleet {
+		fleet[i] = Vehicle{Id: VehicleID(fmt.Sprintf("V%d", i+1)), Capacity: capacity, Speed: float64(speed)}
+	}
+	return fleet
+}
+
+// Capacity of the largest vehicle, packages heavier than it can not be delivered
+func (f Fleet) MaxCapacity() Weight {
+	var capacity Weight
+	for _, vehicle := range f {
+		if vehicle.Capacity > capacity {
+			capacity = vehicle.Capacity
+		}
+	}
+	return capacity
 }
 
 type Shipment []*PackageDetails
@@ -1,13 +1,86 @@
-interface Condition {
-    fact : "distance" | "weight"
-    operator : "lessThan" | "greaterThanOrEqual" | "lessThanOrEqual"
-    value : number
+type Fact =
+    | "distance"
+    | "weight"
+    | "baseDeliveryCost"
+    | "deliveryCost"
+    | "packageCount"
+    | "estDeliveryTime"
+    | "codePrefix"
+type Operator =
+    | "lessThan"
+    | "greaterThanOrEqual"
+    | "lessThanOrEqual"
+    | "greaterThan"
+    | "equal"
+    | "notEqual"
+    | "between"
+    | "in"
+
+export interface Condition {
+    fact : Fact
+    operator : Operator
+    // compared value, not used by between and in
+    value? : number
+    // compared value of text facts (codePrefix)
+    text? : string
+    // bounds (between) or set of values (in)
+    values? : number[]
+    // between excludes both bounds
+    exclusive? : boolean
   }
+
+  // Condition tree, exactly one of all, any, not or a condition
+  export type Rule =
+    | Condition
+    | { all : Rule[] }
+    | { any : Rule[] }
+    | { not : Rule }
+
+  type Day =
+    | "monday"
+    | "tuesday"
+    | "wednesday"
+    | "thursday"
+    | "friday"
+    | "saturday"
+    | "sunday"
+
+  // Recurring window, time of day as HH:MM
+  interface Schedule {
+    days? : Day[]
+    from? : string
+    until? : string
+  }
+
   interface Offer {
     code  : string
+    // fraction (percentage) or amount (flat)
     discount : number
-    conditions: Condition[]
+    // percentage by default
+    discountType? : "percentage" | "flat"
+    // cap and floor (amount) of percentage discount
+    maxDiscount? : number
+    minDiscount? : number
+    // all of them should be satisfied
+    conditions? : Condition[]
+    // should be satisfied along with conditions
+    rules? : Rule
+    // RFC 3339 timestamps, validFrom inclusive and validUntil exclusive
+    validFrom? : string
+    validUntil? : string
+    schedule? : Schedule
+    // exclusive by default
+    stacking? : "exclusive" | "stackable" | "bestOf"
+    // stacked offers are applied in ascending order of priority
+    priority? : number
+    // additive (on the original amount) by default, sequential on the remaining amount
+    stackMode? : "additive" | "sequential"
+    // considered by auto apply (true by default)
+    autoApply? : boolean
+    // redemption limits, in total and per customer
+    maxRedemptions? : number
+    maxPerCustomer? : number
   }
   
   export type Offers = Offer[]
-  
\ No newline at end of file
+  
@@ -1,7 +1,108 @@
 import {JSONSchemaType} from "ajv"
-import { Offers } from "./models";
+import { Condition, Offers } from "./models";
+
+const condition: JSONSchemaType<Condition> = {
+    type: "object",
+    properties: {
+        fact: {
+        type: "string",
+        enum: [
+            "distance",
+            "weight",
+            "baseDeliveryCost",
+            "deliveryCost",
+            "packageCount",
+            "estDeliveryTime",
+            "codePrefix",
+        ]
+        },
+        operator: {
+        type: "string",
+        enum: [
+            "lessThan",
+            "greaterThanOrEqual",
+            "lessThanOrEqual",
+            "greaterThan",
+            "equal",
+            "notEqual",
+            "between",
+            "in",
+        ]
+        },
+        value: {
+        type: "number",
+        nullable: true
+        },
+        text: {
+        type: "string",
+        nullable: true
+        },
+        values: {
+        type: "array",
+        items: {
+            type: "number"
+        },
+        nullable: true
+        },
+        exclusive: {
+        type: "boolean",
+        nullable: true
+        }
+    },
+    required: ["fact", "operator"],
+    allOf: [
+        {
+        // text facts compares text using equal, notEqual
+        if: { properties: { fact: { const: "codePrefix" } } },
+        then: { required: ["text"], properties: { operator: { enum: ["equal", "notEqual"] } } },
+        else: { not: { required: ["text"] } },
+        },
+        {
+        // between requires lower and upper bounds
+        if: { properties: { operator: { const: "between" } } },
+        then: { required: ["values"], properties: { values: { minItems: 2, maxItems: 2 } } },
+        },
+        {
+        // in requires a set of values
+        if: { properties: { operator: { const: "in" } } },
+        then: { required: ["values"], properties: { values: { minItems: 1 } } },
+        },
+        {
+        // rest of the operators compares a single value
+        if: { anyOf: [{ properties: { operator: { enum: ["between", "in"] } } }, { properties: { fact: { const: "codePrefix" } } }] },
+        else: { required: ["value"], not: { required: ["values"] } },
+        },
+    ],
+    additionalProperties: false,
+}
 
 const schema: JSONSchemaType<Offers> = {
+    definitions: {
+        // exactly one of all, any, not or a condition
+        rule: {
+            oneOf: [
+                condition,
+                {
+                type: "object",
+                properties: { all: { type: "array", items: { $ref: "#/definitions/rule" }, minItems: 1 } },
+                required: ["all"],
+                additionalProperties: false,
+                },
+                {
+                type: "object",
+                properties: { any: { type: "array", items: { $ref: "#/definitions/rule" }, minItems: 1 } },
+                required: ["any"],
+                additionalProperties: false,
+                },
+                {
+                type: "object",
+                properties: { not: { $ref: "#/definitions/rule" } },
+                required: ["not"],
+                additionalProperties: false,
+                },
+            ],
+        },
+    },
     type: "array",
     items: {
         type: "object",
@@ -10,37 +111,106 @@ const schema: JSONSchemaType<Offers> = {
             type: "string"
         },
         discount: {
-            type: "number"
+            type: "number",
+            minimum: 0,
+        },
+        discountType: {
+            type: "string",
+            enum: ["percentage", "flat"],
+            nullable: true,
+        },
+        // cap and floor (amount) of percentage discount
+        maxDiscount: {
+            type: "number",
+            minimum: 0,
+            nullable: true,
+        },
+        minDiscount: {
+            type: "number",
+            minimum: 0,
+            nullable: true,
         },
         conditions: {
             type: "array",
-            items: {
+            items: condition,
+            minItems: 1,
+            maxItems: 30,
+            nullable: true,
+        },
+        rules: {
+            $ref: "#/definitions/rule"
+        },
+        validFrom: {
+            type: "string",
+            pattern: "^\\d{4}-\\d{2}-\\d{2}T\\d{2}:\\d{2}:\\d{2}",
+            nullable: true,
+        },
+        validUntil: {
+            type: "string",
+            pattern: "^\\d{4}-\\d{2}-\\d{2}T\\d{2}:\\d{2}:\\d{2}",
+            nullable: true,
+        },
+        stacking: {
+            type: "string",
+            enum: ["exclusive", "stackable", "bestOf"],
+            nullable: true,
+        },
+        priority: {
+            type: "integer",
+            nullable: true,
+        },
+        stackMode: {
+            type: "string",
+            enum: ["additive", "sequential"],
+            nullable: true,
+        },
+        autoApply: {
+            type: "boolean",
+            nullable: true,
+        },
+        maxRedemptions: {
+            type: "integer",
+            minimum: 1,
+            nullable: true,
+        },
+        maxPerCustomer: {
+            type: "integer",
+            minimum: 1,
+            nullable: true,
+        },
+        schedule: {
             type: "object",
             properties: {
-                fact: {
-                type: "string",
-                enum: ["distance", "weight"]
+                days: {
+                    type: "array",
+                    items: {
+                        type: "string",
+                        enum: ["monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"],
+                    },
+                    minItems: 1,
+                    nullable: true,
                 },
-                operator: {
-                type: "string",
-                enum: ["lessThan", "greaterThanOrEqual", "lessThanOrEqual"]
-                },
-                value: {
-                type: "number"
-                }
+                from: { type: "string", pattern: "^\\d{2}:\\d{2}$", nullable: true },
+                until: { type: "string", pattern: "^\\d{2}:\\d{2}$", nullable: true },
             },
-            required: ["fact", "operator", "value"],
+            // days, or time of day window (from and until) or both
+            anyOf: [{ required: ["days"] }, { required: ["from", "until"] }],
+            dependentRequired: { from: ["until"], until: ["from"] },
             additionalProperties: false,
-            },
-            minItems: 1,
-            maxItems: 30,
+            nullable: true,
         },
         },
-        required: ["code", "discount", "conditions"],
+        required: ["code", "discount"],
+        // conditions, rules or both
+        anyOf: [{ required: ["conditions"] }, { required: ["rules"] }],
+        // percentage (default) is a fraction, flat is an amount without cap and floor
+        if: { properties: { discountType: { const: "flat" } }, required: ["discountType"] },
+        then: { not: { anyOf: [{ required: ["maxDiscount"] }, { required: ["minDiscount"] }] } },
+        else: { properties: { discount: { maximum: 1 } } },
         additionalProperties: false,
     },
     minItems: 1,
     maxItems: 50,
 }
 
-export default schema
\ No newline at end of file
+export default schema
@@ -2,145 +2,157 @@ package delivery_svc
 
 import (
 	"math"
-	"sort"
 
 	"github.com/lakshmaji/delivery-shell/models"
 	"github.com/lakshmaji/delivery-shell/services/offers_svc"
 	"github.com/lakshmaji/delivery-shell/utils/common_utils"
+	"github.com/lakshmaji/delivery-shell/utils/delivery_utils"
 )
 
 type defaultService struct {
-	offer_svc offers_svc.OffersService
+	offer_svc        offers_svc.OffersService
+	rateCard         models.RateCard
+	weightResolution models.Weight
+	strategy         ShipmentStrategy
 }
 
-func NewDeliveryService(offer_svc offers_svc.OffersService) DeliveryService {
+// Delivery service which prices packages using the given (active) rate card
+func NewDeliveryService(offer_svc offers_svc.OffersService, rateCard models.RateCard) DeliveryService {
+	return NewDeliveryServiceWithOptions(offer_svc, rateCard, Options{})
+}
+
+// Delivery service with optional behaviour (weight resolution and strategy of shipment planning)
+func NewDeliveryServiceWithOptions(offer_svc offers_svc.OffersService, rateCard models.RateCard, options Options) DeliveryService {
+	if options.WeightResolution <= 0 {
+		options.WeightResolution = DefaultWeightResolution
+	}
+	if options.Strategy == nil {
+		options.Strategy = MaxLoad
+	}
 	return &defaultService{
-		offer_svc: offer_svc,
+		offer_svc:        offer_svc,
+		rateCard:         rateCard,
+		weightResolution: options.WeightResolution,
+		strategy:         options.Strategy,
 	}
 }
 
-func (p *defaultService) CalculateDeliveryCost(weight models.Weight, distance models.Distance, baseDeliveryCost models.BaseDeliveryCost) float64 {
-	return float64(baseDeliveryCost) + (weight * 10) + (distance * 5)
+func (p *defaultService) DeliveryCostBreakdown(weight models.Weight, distance models.Distance) []models.SlabCharge {
+	rounding := p.rateCard.RoundingMode()
+	charges := delivery_utils.SlabCharges(models.FactWeight, weight, p.rateCard.SlabsFor(models.FactWeight), rounding)
+	return append(charges, delivery_utils.SlabCharges(models.FactDistance, distance, p.rateCard.SlabsFor(models.FactDistance), rounding)...)
 }
 
-func (p *defaultService) CalculateDiscount(weight models.Weight, distance models.Distance, code models.OfferCode, deliveryCost float64) (float64, error) {
-	return p.offer_svc.ApplicableDiscount(deliveryCost, code, weight, distance)
+func (p *defaultService) CalculateDeliveryCost(weight models.Weight, distance models.Distance, baseDeliveryCost models.BaseDeliveryCost) models.Money {
+	rounding := p.rateCard.RoundingMode()
+	deliveryCost := models.NewMoney(float64(baseDeliveryCost), rounding).Add(delivery_utils.SlabChargesTotal(p.DeliveryCostBreakdown(weight, distance)))
+	minimumCharge := models.NewMoney(p.rateCard.MinimumCharge, rounding)
+	if deliveryCost < minimumCharge {
+		return minimumCharge
+	}
+	return deliveryCost
 }
 
-func (p *defaultService) EstDeliveryTime(items []*models.PackageDetails, maxWeight int, noOfVehicles int, maxSpeed int) models.PackageDeliveryTime {
-	vehicles := initVehicles(noOfVehicles)
-	var itemsDeliveryTime models.PackageDeliveryTime = make(models.PackageDeliveryTime)
-	var minVehicle *models.Vehicle
-
-	for len(items) > 0 {
-		buffer := pickItemByMaxNetWeight(items, maxWeight)
-		shipmentItems := getShipmentItems(items, buffer, maxWeight)
-
-		if len(shipmentItems) == 0 {
-			break
-		}
+func (p *defaultService) CalculateDiscount(fact models.Fact, code models.OfferCode, deliveryCost models.Money) (models.DiscountResult, error) {
+	return p.offer_svc.ApplicableDiscount(deliveryCost, code, fact)
+}
 
-		maxDeliveryTime := float64(math.MinInt64)
-		minWaitTime := float64(math.MaxFloat64)
+func (p *defaultService) CalculateDiscounts(fact models.Fact, codes []models.OfferCode, deliveryCost models.Money) ([]models.DiscountResult, error) {
+	return p.offer_svc.ApplicableDiscounts(deliveryCost, codes, fact)
+}
 
-		for _, vehicle := range vehicles {
-			if vehicle.WaitTime < minWaitTime {
-				minWaitTime = vehicle.WaitTime
-				minVehicle = vehicle
-			}
-		}
+func (p *defaultService) ExplainDiscounts(fact models.Fact, codes []models.OfferCode, results []models.DiscountResult) ([]models.OfferExplanation, error) {
+	return p.offer_svc.Explain(codes, fact, results)
+}
 
-		var maxRoundTripTime float64
-		for _, item := range shipmentItems {
-			deliveredIn := float64(item.Distance) / float64(maxSpeed)
-			if maxDeliveryTime < deliveredIn {
-				maxDeliveryTime = common_utils.ToFixed(deliveredIn, 2)
-			}
+func (p *defaultService) CommitRedemptions() error {
+	return p.offer_svc.CommitRedemptions()
+}
 
-			DeliveredIn := common_utils.ToFixed(deliveredIn+minVehicle.WaitTime, 2)
-			maxRoundTripTime = minVehicle.WaitTime + common_utils.ToFixed(maxDeliveryTime*2, 2)
+func (p *defaultService) ReleaseRedemptions() {
+	p.offer_svc.ReleaseRedemptions()
+}
 
-			itemsDeliveryTime[item.Id] = DeliveredIn
+func (p *defaultService) OverweightPackages(items []*models.PackageDetails, fleet models.Fleet) []*models.PackageDetails {
+	capacity := delivery_utils.CapacityUnits(fleet.MaxCapacity(), p.weightResolution)
+	var overweight []*models.PackageDetails
+	for _, item := range items {
+		if delivery_utils.WeightUnits(item.Weight, p.weightResolution) > capacity {
+			overweight = append(overweight, item)
 		}
-		minVehicle.WaitTime = maxRoundTripTime
-
-		items = removeItems(items, shipmentItems)
-
 	}
+	return overweight
+}
 
-	return itemsDeliveryTime
-
+func (p *defaultService) EstDeliveryTime(items []*models.PackageDetails, fleet models.Fleet) models.PackageDeliveryTime {
+	manifest, _ := p.PlanTrips(items, fleet)
+	return manifest.DeliveryTimes()
 }
 
-func pickItemByMaxNetWeight(items []*models.PackageDetails, maxWeight int) [][]weightBuffer {
-	buffer := make([][]weightBuffer, len(items)+1)
-	for i := 0; i < len(buffer); i++ {
-		buffer[i] = make([]weightBuffer, maxWeight+1)
+func (p *defaultService) PlanTrips(items []*models.PackageDetails, fleet models.Fleet) (models.Manifest, []*models.PackageDetails) {
+	vehicles := initVehicles(fleet)
+	manifest := make(models.Manifest, len(fleet))
+	// vehicles still dispatched (index of the fleet)
+	available := make([]int, len(fleet))
+	for i, vehicle := range fleet {
+		manifest[i] = models.VehicleTrips{Vehicle: vehicle}
+		available[i] = i
 	}
+	planner := newShipmentPlanner(items, p.weightResolution, p.strategy)
 
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].Weight == items[j].Weight {
-			return items[i].Distance < items[j].Distance
-		}
-		return items[i].Weight < items[j].Weight
-	})
-
-	for i := 1; i <= len(items); i++ {
-		for j := 1; j <= maxWeight; j++ {
-			if int(items[i-1].Weight) > j {
-				buffer[i][j] = buffer[i-1][j]
-			} else {
-				prevItem := buffer[i-1][j-int(items[i-1].Weight)]
-				filledWeight := int(prevItem.computedWeight) + int(items[i-1].Weight)
-
-				buffer[i][j] = weightBuffer{
-					computedWeight: common_utils.MaxVal(filledWeight, buffer[i-1][j].computedWeight),
-				}
+	for !planner.done() && len(available) > 0 {
+		maxDeliveryTime := float64(math.MinInt64)
+		minWaitTime := float64(math.MaxFloat64)
+
+		var minVehicle int
+		for i, index := range available {
+			if vehicles[index].WaitTime < minWaitTime {
+				minWaitTime = vehicles[index].WaitTime
+				minVehicle = i
 			}
 		}
-	}
+		index := available[minVehicle]
+		vehicle := vehicles[index]
 
-	return buffer
-}
+		shipmentItems := planner.next(vehicle.Capacity)
+		// nothing left the vehicle can carry
+		if len(shipmentItems) == 0 {
+			available = append(available[:minVehicle], available[minVehicle+1:]...)
+			continue
+		}
 
-func removeItems(items []*models.PackageDetails, shippedItems []*models.PackageDetails) []*models.PackageDetails {
-	for _, shippedItem := range shippedItems {
-		for i, item := range items {
-			if item.IsSamePackage(*shippedItem) {
-				items = append(items[:i], items[i+1:]...)
-				break
+		trip := models.Trip{Departure: vehicle.WaitTime}
+		var maxRoundTripTime float64
+		for _, item := range shipmentItems {
+			deliveredIn := float64(item.Distance) / vehicle.Speed
+			if maxDeliveryTime < deliveredIn {
+				maxDeliveryTime = common_utils.ToFixed(deliveredIn, 2)
 			}
-		}
-	}
-	return items
-}
 
-func getShipmentItems(items []*models.PackageDetails, buffer [][]weightBuffer, maxWeight int) []*models.PackageDetails {
-	var bag []*models.PackageDetails
-	i := len(items)
-	j := maxWeight
-
-	for i > 0 && j > 0 {
-		if buffer[i][j].computedWeight == buffer[i-1][j].computedWeight {
-			i--
-		} else {
-			bag = append(bag, items[i-1])
-			j -= int(items[i-1].Weight)
-			i--
+			DeliveredIn := common_utils.ToFixed(deliveredIn+vehicle.WaitTime, 2)
+			maxRoundTripTime = vehicle.WaitTime + common_utils.ToFixed(maxDeliveryTime*2, 2)
+
+			trip.Packages = append(trip.Packages, models.TripPackage{Id: item.Id, Weight: item.Weight, Distance: item.Distance, DeliveredAt: DeliveredIn})
+			trip.Load += item.Weight
+			if item.Distance > trip.FurthestDistance {
+				trip.FurthestDistance = item.Distance
+			}
 		}
+		trip.Return = maxRoundTripTime
+		manifest[index].Trips = append(manifest[index].Trips, trip)
+		vehicle.WaitTime = maxRoundTripTime
 	}
 
-	return bag
-}
-
-type weightBuffer struct {
-	computedWeight int
+	return manifest, planner.unshipped()
 }
 
-func initVehicles(noOfVehicles int) []*models.Vehicle {
-	vehicles := make([]*models.Vehicle, noOfVehicles)
-	for i := 0; i < noOfVehicles; i++ {
-		vehicles[i] = &models.Vehicle{WaitTime: 0}
+// Vehicles being planned, each one waiting until it is available
+func initVehicles(fleet models.Fleet) []*models.Vehicle {
+	vehicles := make([]*models.Vehicle, len(fleet))
+	for i := range fleet {
+		vehicle := fleet[i]
+		vehicle.WaitTime = vehicle.AvailableAt
+		vehicles[i] = &vehicle
 	}
 	return vehicles
 }
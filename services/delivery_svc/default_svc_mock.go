@@ -31,6 +31,28 @@ func NewOffersSvcMock() offers_svc.OffersService {
 	return &offerServiceMock{}
 }
 
-func (*offerServiceMock) ApplicableDiscount(deliveryCost float64, code models.OfferCode, weight models.Weight, distance models.Distance) (float64, error) {
-	return 0.05, nil
+func (*offerServiceMock) ApplicableDiscount(deliveryCost models.Money, code models.OfferCode, fact models.Fact) (models.DiscountResult, error) {
+	return models.DiscountResult{Code: code, Amount: models.Money(5), Status: models.DiscountApplied}, nil
 }
+
+func (*offerServiceMock) ApplicableDiscounts(deliveryCost models.Money, codes []models.OfferCode, fact models.Fact) ([]models.DiscountResult, error) {
+	results := []models.DiscountResult{}
+	for _, code := range codes {
+		results = append(results, models.DiscountResult{Code: code, Amount: models.Money(5), Status: models.DiscountApplied})
+	}
+	return results, nil
+}
+
+func (*offerServiceMock) Explain(codes []models.OfferCode, fact models.Fact, results []models.DiscountResult) ([]models.OfferExplanation, error) {
+	explanations := []models.OfferExplanation{}
+	for _, code := range codes {
+		explanations = append(explanations, models.OfferExplanation{Code: code, Found: true, Status: models.DiscountApplied})
+	}
+	return explanations, nil
+}
+
+func (*offerServiceMock) CommitRedemptions() error {
+	return nil
+}
+
+func (*offerServiceMock) ReleaseRedemptions() {}
@@ -1,10 +1,15 @@
 package delivery_svc
 
 import (
+	"fmt"
+	"math/rand"
 	"reflect"
 	"testing"
+	"testing/quick"
 
 	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/common_utils"
+	"github.com/lakshmaji/delivery-shell/utils/delivery_utils"
 )
 
 func TestCalculateDeliveryCost(t *testing.T) {
@@ -14,24 +19,80 @@ func TestCalculateDeliveryCost(t *testing.T) {
 		baseDeliveryCost models.BaseDeliveryCost
 	}
 	tests := []struct {
-		name string
-		args args
-		want float64
+		name     string
+		rateCard models.RateCard
+		args     args
+		want     models.Money
 	}{
 		{
-			name: "TestCalculateDeliveryCost",
+			name:     "TestCalculateDeliveryCost",
+			rateCard: models.DefaultRateCard(),
+			args: args{
+				weight:           models.Weight(10),
+				distance:         models.Distance(5),
+				baseDeliveryCost: models.BaseDeliveryCost(100),
+			},
+			want: models.Money((100 + (10 * 10) + (5 * 5)) * models.MoneyScale),
+		},
+		{
+			name:     "with custom rate card",
+			rateCard: models.RateCard{Name: "express", PerKg: 12.5, PerKm: 6},
+			args: args{
+				weight:           models.Weight(10),
+				distance:         models.Distance(5),
+				baseDeliveryCost: models.BaseDeliveryCost(100),
+			},
+			want: models.Money((100 + (10 * 12.5) + (5 * 6)) * models.MoneyScale),
+		},
+		{
+			name:     "when delivery cost is below minimum charge",
+			rateCard: models.RateCard{Name: "express", PerKg: 1, PerKm: 1, MinimumCharge: 150},
 			args: args{
 				weight:           models.Weight(10),
 				distance:         models.Distance(5),
 				baseDeliveryCost: models.BaseDeliveryCost(100),
 			},
-			want: float64(100) + (float64(10) * 10) + (float64(5) * 5),
+			want: models.Money(150 * models.MoneyScale),
+		},
+		{
+			name:     "rounds half up by default",
+			rateCard: models.RateCard{Name: "fractional", PerKg: 1, PerKm: 1},
+			args: args{
+				weight:           models.Weight(0.125),
+				distance:         models.Distance(0),
+				baseDeliveryCost: models.BaseDeliveryCost(0),
+			},
+			want: models.Money(13),
+		},
+		{
+			name:     "with truncate rounding mode",
+			rateCard: models.RateCard{Name: "fractional", PerKg: 1, PerKm: 1, Rounding: models.RoundTruncate},
+			args: args{
+				weight:           models.Weight(0.125),
+				distance:         models.Distance(0),
+				baseDeliveryCost: models.BaseDeliveryCost(0),
+			},
+			want: models.Money(12),
+		},
+		{
+			name: "with weight and distance slabs",
+			rateCard: models.RateCard{
+				Name:          "slabs",
+				WeightSlabs:   &models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: []models.Slab{{From: 0, To: 5, Rate: 12}, {From: 5, Rate: 10}}},
+				DistanceSlabs: &models.SlabPricing{Mode: models.SlabModeFlat, Slabs: []models.Slab{{From: 0, To: 50, Rate: 6}, {From: 50, Rate: 4}}},
+			},
+			args: args{
+				weight:           models.Weight(10),
+				distance:         models.Distance(100),
+				baseDeliveryCost: models.BaseDeliveryCost(100),
+			},
+			want: models.Money((100 + (5 * 12) + (5 * 10) + (100 * 4)) * models.MoneyScale),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := NewDeliveryService(NewOffersSvcMock())
+			svc := NewDeliveryService(NewOffersSvcMock(), tt.rateCard)
 			if got := svc.CalculateDeliveryCost(tt.args.weight, tt.args.distance, tt.args.baseDeliveryCost); got != tt.want {
 				t.Errorf("CalculateDeliveryCost() = %v, want %v", got, tt.want)
 			}
@@ -39,36 +100,79 @@ func TestCalculateDeliveryCost(t *testing.T) {
 	}
 }
 
+func TestDeliveryCostBreakdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		rateCard models.RateCard
+		weight   models.Weight
+		distance models.Distance
+		want     []models.SlabCharge
+	}{
+		{
+			name:     "with linear rates",
+			rateCard: models.DefaultRateCard(),
+			weight:   models.Weight(10),
+			distance: models.Distance(5),
+			want: []models.SlabCharge{
+				{Fact: "weight", Units: 10, Rate: 10, Amount: 10000},
+				{Fact: "distance", Units: 5, Rate: 5, Amount: 2500},
+			},
+		},
+		{
+			name: "with weight slabs",
+			rateCard: models.RateCard{
+				Name:        "slabs",
+				PerKm:       5,
+				WeightSlabs: &models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: []models.Slab{{From: 0, To: 5, Rate: 12}, {From: 5, Rate: 10}}},
+			},
+			weight:   models.Weight(10),
+			distance: models.Distance(5),
+			want: []models.SlabCharge{
+				{Fact: "weight", From: 0, To: 5, Units: 5, Rate: 12, Amount: 6000},
+				{Fact: "weight", From: 5, Units: 5, Rate: 10, Amount: 5000},
+				{Fact: "distance", Units: 5, Rate: 5, Amount: 2500},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewDeliveryService(NewOffersSvcMock(), tt.rateCard)
+			if got := svc.DeliveryCostBreakdown(tt.weight, tt.distance); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeliveryCostBreakdown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCalculateDiscount(t *testing.T) {
 	type args struct {
-		weight       models.Weight
-		distance     models.Distance
+		fact         models.Fact
 		code         models.OfferCode
-		deliveryCost float64
+		deliveryCost models.Money
 	}
 	tests := []struct {
 		name string
 		args args
-		want float64
+		want models.Money
 	}{
 		{
 			name: "TestCalculateDiscount",
 			args: args{
-				weight:       models.Weight(10),
-				distance:     models.Distance(100),
+				fact:         models.Fact{Weight: models.Weight(10), Distance: models.Distance(100)},
 				code:         models.OfferCode("OFR003"),
-				deliveryCost: 100,
+				deliveryCost: 10000,
 			},
-			want: 0.05,
+			want: 5,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := NewDeliveryService(NewOffersSvcMock())
-			got, _ := svc.CalculateDiscount(tt.args.weight, tt.args.distance, tt.args.code, tt.args.deliveryCost)
-			if got != tt.want {
-				t.Errorf("CalculateDiscount() = %v, want %v", got, tt.want)
+			svc := NewDeliveryService(NewOffersSvcMock(), models.DefaultRateCard())
+			got, _ := svc.CalculateDiscount(tt.args.fact, tt.args.code, tt.args.deliveryCost)
+			if got.Amount != tt.want {
+				t.Errorf("CalculateDiscount() = %v, want %v", got.Amount, tt.want)
 			}
 		})
 	}
@@ -78,7 +182,7 @@ func TestCalculateEstTime(t *testing.T) {
 	type args struct {
 		// assumption input will be always sanitized (weight)
 		items        []*models.PackageDetails
-		maxWeight    int
+		maxWeight    models.Weight
 		noOfVehicles int
 		maxSpeed     int
 	}
@@ -391,15 +495,349 @@ func TestCalculateEstTime(t *testing.T) {
 				"PKG4": 1,
 			},
 		},
+		{
+			name: "Sample 10 (fractional weights together beyond capacity)",
+			args: args{
+				items: []*models.PackageDetails{
+					{
+						Id:       "PKG1",
+						Weight:   50.5,
+						Distance: 10,
+						Code:     "OFR001",
+					},
+					{
+						Id:       "PKG2",
+						Weight:   49.6,
+						Distance: 10,
+						Code:     "OFR002",
+					},
+				},
+				noOfVehicles: 1,
+				maxSpeed:     10,
+				maxWeight:    100,
+			},
+			want: models.PackageDeliveryTime{
+				"PKG1": 1,
+				"PKG2": 3,
+			},
+		},
+		{
+			name: "Sample 11 (fractional capacity)",
+			args: args{
+				items: []*models.PackageDetails{
+					{
+						Id:       "PKG1",
+						Weight:   24.9,
+						Distance: 10,
+						Code:     "OFR001",
+					},
+					{
+						Id:       "PKG2",
+						Weight:   24.6,
+						Distance: 20,
+						Code:     "OFR002",
+					},
+				},
+				noOfVehicles: 1,
+				maxSpeed:     10,
+				maxWeight:    49.5,
+			},
+			want: models.PackageDeliveryTime{
+				"PKG1": 1,
+				"PKG2": 2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewDeliveryService(NewOffersSvcMock(), models.DefaultRateCard())
+			got := svc.EstDeliveryTime(tt.args.items, models.NewFleet(tt.args.noOfVehicles, tt.args.maxSpeed, tt.args.maxWeight))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EstDeliveryTime() = %v, want %v", got, tt.want)
+			}
+		})
 	}
+}
 
+func TestEstDeliveryTimeFleet(t *testing.T) {
+	fleet := models.Fleet{
+		{Id: "BIKE1", Type: "bike", Capacity: 20, Speed: 40},
+		{Id: "VAN1", Type: "van", Capacity: 100, Speed: 60},
+		{Id: "TRUCK1", Type: "truck", Capacity: 300, Speed: 50, AvailableAt: 1},
+	}
+	tests := []struct {
+		name  string
+		items []*models.PackageDetails
+		want  models.PackageDeliveryTime
+	}{
+		{
+			name:  "shipments within capacity of the first available vehicle",
+			items: packages("PKG1 15 20", "PKG2 80 60", "PKG3 250 100", "PKG4 10 40"),
+			// BIKE1 carries PKG1, VAN1 carries PKG2 and PKG4, BIKE1 can not carry PKG3 so TRUCK1 (available at 1 hr) does
+			want: models.PackageDeliveryTime{"PKG1": 0.5, "PKG2": 1, "PKG3": 3, "PKG4": 0.66},
+		},
+		{
+			name:  "packages heavier than every vehicle are not shipped",
+			items: packages("PKG1 15 20", "PKG5 400 10"),
+			want:  models.PackageDeliveryTime{"PKG1": 0.5},
+		},
+		{
+			name:  "packages heavier than the first available vehicle",
+			items: packages("PKG1 30 20", "PKG2 60 30"),
+			want:  models.PackageDeliveryTime{"PKG1": 0.33, "PKG2": 0.5},
+		},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := NewDeliveryService(NewOffersSvcMock())
-			got := svc.EstDeliveryTime(tt.args.items, tt.args.maxWeight, tt.args.noOfVehicles, tt.args.maxSpeed)
+			svc := NewDeliveryService(NewOffersSvcMock(), models.DefaultRateCard())
+			got := svc.EstDeliveryTime(tt.items, fleet)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("EstDeliveryTime() = %v, want %v", got, tt.want)
 			}
 		})
 	}
+
+	// vehicles of the fleet are not modified by planning
+	if fleet[0].WaitTime != 0 {
+		t.Errorf("expected fleet to be unchanged, received %+v", fleet[0])
+	}
+}
+
+func TestPlanTrips(t *testing.T) {
+	fleet := models.Fleet{
+		{Id: "BIKE1", Type: "bike", Capacity: 20, Speed: 40},
+		{Id: "VAN1", Type: "van", Capacity: 100, Speed: 60},
+		{Id: "TRUCK1", Type: "truck", Capacity: 300, Speed: 50, AvailableAt: 1},
+		{Id: "TRUCK2", Type: "truck", Capacity: 300, Speed: 50, AvailableAt: 4},
+	}
+	svc := NewDeliveryService(NewOffersSvcMock(), models.DefaultRateCard())
+	manifest, unshipped := svc.PlanTrips(packages("PKG1 15 20", "PKG2 80 60", "PKG3 250 100", "PKG4 10 40", "PKG5 5 10", "PKG6 350 10"), fleet)
+
+	expected := models.Manifest{
+		{Vehicle: fleet[0], Trips: []models.Trip{
+			{Departure: 0, Packages: []models.TripPackage{{Id: "PKG1", Weight: 15, Distance: 20, DeliveredAt: 0.5}, {Id: "PKG5", Weight: 5, Distance: 10, DeliveredAt: 0.25}}, Load: 20, FurthestDistance: 20, Return: 1},
+		}},
+		{Vehicle: fleet[1], Trips: []models.Trip{
+			{Departure: 0, Packages: []models.TripPackage{{Id: "PKG2", Weight: 80, Distance: 60, DeliveredAt: 1}, {Id: "PKG4", Weight: 10, Distance: 40, DeliveredAt: 0.66}}, Load: 90, FurthestDistance: 60, Return: 2},
+		}},
+		{Vehicle: fleet[2], Trips: []models.Trip{
+			{Departure: 1, Packages: []models.TripPackage{{Id: "PKG3", Weight: 250, Distance: 100, DeliveredAt: 3}}, Load: 250, FurthestDistance: 100, Return: 5},
+		}},
+		// no packages left by the time it is available
+		{Vehicle: fleet[3]},
+	}
+	if !reflect.DeepEqual(manifest, expected) {
+		t.Errorf("PlanTrips() = %+v, want %+v", manifest, expected)
+	}
+	// heavier than every vehicle
+	if len(unshipped) != 1 || unshipped[0].Id != "PKG6" {
+		t.Errorf("PlanTrips() unshipped = %v, want [PKG6]", unshipped)
+	}
+}
+
+func TestOverweightPackages(t *testing.T) {
+	fleet := models.Fleet{{Id: "T1", Type: "truck", Capacity: 200.09, Speed: 10}}
+	items := packages("PKG1 200.05 10", "PKG2 200 10", "PKG3 200.01 10")
+
+	tt := []struct {
+		resolution models.Weight
+		expected   []models.PackageID
+	}{
+		{resolution: 0.01, expected: nil},
+		// 2001 units (rounded up) above the capacity of 2000 units (rounded down)
+		{resolution: 0.1, expected: []models.PackageID{"PKG1", "PKG3"}},
+		{resolution: 1, expected: []models.PackageID{"PKG1", "PKG3"}},
+	}
+	for _, test := range tt {
+		t.Run(fmt.Sprint(test.resolution), func(t *testing.T) {
+			svc := NewDeliveryServiceWithOptions(NewOffersSvcMock(), models.DefaultRateCard(), Options{WeightResolution: test.resolution})
+			var result []models.PackageID
+			for _, item := range svc.OverweightPackages(items, fleet) {
+				result = append(result, item.Id)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("OverweightPackages() = %v, want %v", result, test.expected)
+			}
+			// overweight packages are the ones which are not shipped
+			_, unshipped := svc.PlanTrips(append([]*models.PackageDetails{}, items...), fleet)
+			if len(unshipped) != len(test.expected) {
+				t.Errorf("PlanTrips() unshipped = %v, want %v", unshipped, test.expected)
+			}
+		})
+	}
+}
+
+func TestPlanTripsProperties(t *testing.T) {
+	property := func(batch plannerBatch, seed int64) bool {
+		// fleet of vehicles of random capacity, speed and availability
+		r := rand.New(rand.NewSource(seed))
+		fleet := models.Fleet{}
+		for i := 0; i < 1+r.Intn(4); i++ {
+			fleet = append(fleet, models.Vehicle{Id: models.VehicleID(fmt.Sprintf("V%d", i+1)), Capacity: common_utils.ToFixed(1+r.Float64()*40, 1), Speed: float64(10 + r.Intn(60)), AvailableAt: float64(r.Intn(3))})
+		}
+		svc := NewDeliveryServiceWithOptions(NewOffersSvcMock(), models.DefaultRateCard(), Options{WeightResolution: batch.Resolution})
+		manifest, unshipped := svc.PlanTrips(append([]*models.PackageDetails{}, batch.Items...), fleet)
+
+		shipped := make(map[models.PackageID]bool)
+		for i, vehicle := range manifest {
+			if !reflect.DeepEqual(vehicle.Vehicle, fleet[i]) {
+				t.Logf("vehicle %d of the manifest %+v, expected %+v", i, vehicle.Vehicle, fleet[i])
+				return false
+			}
+			availableAt := vehicle.Vehicle.AvailableAt
+			for _, trip := range vehicle.Trips {
+				if trip.Departure < availableAt || trip.Return < trip.Departure {
+					t.Logf("trip of %s departs at %v before the vehicle is available at %v", vehicle.Vehicle.Id, trip.Departure, availableAt)
+					return false
+				}
+				if trip.Load > vehicle.Vehicle.Capacity+1e-9 {
+					t.Logf("trip of %v kg exceeds capacity of %s %v kg", trip.Load, vehicle.Vehicle.Id, vehicle.Vehicle.Capacity)
+					return false
+				}
+				for _, pkg := range trip.Packages {
+					if shipped[pkg.Id] || pkg.DeliveredAt < trip.Departure || pkg.DeliveredAt > trip.Return || pkg.Distance > trip.FurthestDistance {
+						t.Logf("package %s of trip %+v", pkg.Id, trip)
+						return false
+					}
+					shipped[pkg.Id] = true
+				}
+				availableAt = trip.Return
+			}
+		}
+		// packages fitting any vehicle are shipped, the others are reported
+		var notShipped []*models.PackageDetails
+		for _, item := range batch.Items {
+			weight := delivery_utils.WeightUnits(item.Weight, batch.Resolution)
+			fits := weight > 0 && weight <= delivery_utils.CapacityUnits(fleet.MaxCapacity(), batch.Resolution)
+			if fits != shipped[item.Id] {
+				t.Logf("package %s of %v kg shipped %v, fleet capacity %v kg", item.Id, item.Weight, shipped[item.Id], fleet.MaxCapacity())
+				return false
+			}
+			if !fits {
+				notShipped = append(notShipped, item)
+			}
+		}
+		if !reflect.DeepEqual(unshipped, notShipped) {
+			t.Logf("unshipped packages %v, expected %v", unshipped, notShipped)
+			return false
+		}
+		return reflect.DeepEqual(manifest.DeliveryTimes(), svc.EstDeliveryTime(append([]*models.PackageDetails{}, batch.Items...), fleet))
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(1))}); err != nil {
+		t.Error(err)
+	}
+}
+
+// Random batch of packages with fractional weights, for property based tests of shipment planning
+type plannerBatch struct {
+	Items      []*models.PackageDetails
+	Capacity   models.Weight
+	Resolution models.Weight
+}
+
+func (plannerBatch) Generate(rand *rand.Rand, size int) reflect.Value {
+	resolutions := []models.Weight{1, 0.1, 0.01}
+	batch := plannerBatch{
+		Capacity:   common_utils.ToFixed(1+rand.Float64()*60, 1),
+		Resolution: resolutions[rand.Intn(len(resolutions))],
+	}
+	for i := 0; i < 1+rand.Intn(9); i++ {
+		batch.Items = append(batch.Items, &models.PackageDetails{
+			Id:       models.PackageID(fmt.Sprintf("PKG%d", i+1)),
+			Weight:   common_utils.ToFixed(rand.Float64()*40, 1+rand.Intn(2)),
+			Distance: common_utils.ToFixed(1+rand.Float64()*100, 0),
+		})
+	}
+	return reflect.ValueOf(batch)
+}
+
+func totalWeight(items []*models.PackageDetails) models.Weight {
+	var total models.Weight
+	for _, item := range items {
+		total += item.Weight
+	}
+	return total
+}
+
+// Heaviest load (units) of any subset of the packages within the capacity
+func bestLoad(items []*models.PackageDetails, capacity int, resolution models.Weight) int {
+	best := 0
+	for subset := 0; subset < 1<<len(items); subset++ {
+		load := 0
+		for i, item := range items {
+			if subset&(1<<i) != 0 {
+				load += delivery_utils.WeightUnits(item.Weight, resolution)
+			}
+		}
+		if load <= capacity && load > best {
+			best = load
+		}
+	}
+	return best
+}
+
+func TestPlanShipmentsProperties(t *testing.T) {
+	config := &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(1))}
+
+	t.Run("shipment never exceeds capacity", func(t *testing.T) {
+		property := func(batch plannerBatch) bool {
+			for _, shipment := range planShipments(batch.Items, batch.Capacity, batch.Resolution, MaxLoad) {
+				if totalWeight(shipment) > batch.Capacity+1e-9 {
+					t.Logf("shipment of %v kg exceeds capacity %v kg", totalWeight(shipment), batch.Capacity)
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(property, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("every package within capacity is shipped once", func(t *testing.T) {
+		property := func(batch plannerBatch) bool {
+			items := append([]*models.PackageDetails{}, batch.Items...)
+			shipped := make(map[models.PackageID]int)
+			for _, shipment := range planShipments(batch.Items, batch.Capacity, batch.Resolution, MaxLoad) {
+				for _, item := range shipment {
+					shipped[item.Id]++
+				}
+			}
+			for _, item := range items {
+				weight := delivery_utils.WeightUnits(item.Weight, batch.Resolution)
+				fits := weight > 0 && weight <= delivery_utils.CapacityUnits(batch.Capacity, batch.Resolution)
+				if (fits && shipped[item.Id] != 1) || (!fits && shipped[item.Id] != 0) {
+					t.Logf("package %s of %v kg shipped %d times", item.Id, item.Weight, shipped[item.Id])
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(property, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("every shipment carries the heaviest possible load", func(t *testing.T) {
+		property := func(batch plannerBatch) bool {
+			capacity := delivery_utils.CapacityUnits(batch.Capacity, batch.Resolution)
+			remaining := append([]*models.PackageDetails{}, batch.Items...)
+			for _, shipment := range planShipments(batch.Items, batch.Capacity, batch.Resolution, MaxLoad) {
+				load := 0
+				for _, item := range shipment {
+					load += delivery_utils.WeightUnits(item.Weight, batch.Resolution)
+				}
+				if best := bestLoad(remaining, capacity, batch.Resolution); load != best {
+					t.Logf("shipment load %d units, best %d units", load, best)
+					return false
+				}
+				remaining = removeItems(remaining, shipment)
+			}
+			return true
+		}
+		if err := quick.Check(property, config); err != nil {
+			t.Error(err)
+		}
+	})
 }
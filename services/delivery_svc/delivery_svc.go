@@ -6,8 +6,12 @@ type DeliveryService interface {
 
 	//  Calculates delivery cost for a package
 	//
-	//  Formulae:
-	//   delivery cost = (package total weight * 10) + (distance to destination *5)
+	//  Formulae (using the active rate card):
+	//   delivery cost = base delivery cost + weight slab charges + distance slab charges
+	//
+	//  Linear rates (per kg, per km) are priced as a single open ended slab.
+	//  Every amount is rounded to the nearest minor unit using the rounding mode of the rate card.
+	//  The delivery cost never goes below the minimum charge of the rate card.
 	//
 	//  We could any other factors impacting delivery service charge like weather, surge etc. (without any offer service related code)
 	//
@@ -16,7 +20,14 @@ type DeliveryService interface {
 	//  @param baseDeliveryCost	Base Delivery Cost
 	//
 	//  @return delivery cost
-	CalculateDeliveryCost(weight models.Weight, distance models.Distance, baseDeliveryCost models.BaseDeliveryCost) float64
+	CalculateDeliveryCost(weight models.Weight, distance models.Distance, baseDeliveryCost models.BaseDeliveryCost) models.Money
+	//  Charges of every weight and distance slab contributing to the delivery cost (excluding base delivery cost)
+	//
+	//  @param weight Weight of package
+	//  @param distance Distance to destination
+	//
+	//  @return slab charges (weight slabs followed by distance slabs)
+	DeliveryCostBreakdown(weight models.Weight, distance models.Distance) []models.SlabCharge
 	//  Calculates discount for a package based on applicable Offer code.
 	//  Validate Offer code using offers service. (So that the other consuming services don't require to do this)
 	//
@@ -25,13 +36,66 @@ type DeliveryService interface {
 	//  any additional discount logic apart from OfferCode
 	//
 	//
-	//  @param weight Weight of package
-	//  @param distance Distance to destination
+	//  @param fact Facts of the package (weight, distance, delivery cost etc.) against which offer conditions are evaluated
 	//  @param code Offer code
 	//  @param deliveryCost Delivery cost
 	//
-	//  @return discount
-	CalculateDiscount(weight models.Weight, distance models.Distance, code models.OfferCode, deliveryCost float64) (float64, error)
+	//  @return discount (amount, status and reason when not applied)
+	CalculateDiscount(fact models.Fact, code models.OfferCode, deliveryCost models.Money) (models.DiscountResult, error)
+	//  Calculates discounts for a package having several offer codes, as per the stacking rules of the offers
+	//
+	//  @param fact Facts of the package
+	//  @param codes Offer codes
+	//  @param deliveryCost Delivery cost
+	//
+	//  @return discount of every code (applied ones along with amount)
+	CalculateDiscounts(fact models.Fact, codes []models.OfferCode, deliveryCost models.Money) ([]models.DiscountResult, error)
+	//  Explains why every offer code of a package did or did not apply (outcome of every offer condition)
+	//
+	//  @param fact Facts of the package
+	//  @param codes Offer codes
+	//  @param results Discounts of the codes, as calculated by CalculateDiscounts
+	//
+	//  @return explanation of every code
+	ExplainDiscounts(fact models.Fact, codes []models.OfferCode, results []models.DiscountResult) ([]models.OfferExplanation, error)
+	//  Redemptions of the discounts granted during the run become permanent (offer usage limits)
+	CommitRedemptions() error
+	//  Redemptions of the discounts granted during the run are dropped, when the run fails partway
+	ReleaseRedemptions()
+
+	//  Packages heavier than the largest vehicle of the fleet, weights are compared in units of the weight resolution (as planned)
+	//
+	//  @param items Packages
+	//  @param fleet Vehicles
+	//
+	//  @return overweight packages, in the given order
+	OverweightPackages(items []*models.PackageDetails, fleet models.Fleet) []*models.PackageDetails
+	//  Plans the trips of every vehicle, shipments picked by the shipment strategy (maximum load by default) are dispatched on the first available vehicle
+	//  within the capacity of that vehicle, a vehicle no longer carrying any pending package is not dispatched again
+	//  Fractional weights are planned in units of the weight resolution, a shipment never exceeds the capacity
+	//
+	//  @param items Packages
+	//  @param fleet Vehicles (capacity, speed and availability of each one)
+	//
+	//  @return trips of every vehicle, with est delivery time of every shipped package
+	//  @return packages which are not shipped (ex: heavier than every vehicle), in the given order
+	PlanTrips(items []*models.PackageDetails, fleet models.Fleet) (models.Manifest, []*models.PackageDetails)
+	//  Estimates delivery time of every package, as per the planned trips
+	//
+	//  @param items Packages
+	//  @param fleet Vehicles (capacity, speed and availability of each one)
+	//
+	//  @return est delivery time of every shipped package (packages heavier than every vehicle are not shipped)
+	EstDeliveryTime(items []*models.PackageDetails, fleet models.Fleet) models.PackageDeliveryTime
+}
+
+// Weights are planned in units of 0.01 kg by default
+const DefaultWeightResolution models.Weight = 0.01
 
-	EstDeliveryTime(items []*models.PackageDetails, maxWeight int, noOfVehicles int, maxSpeed int) models.PackageDeliveryTime
+type Options struct {
+	// Smallest weight (kg) told apart by shipment planning, ex: 0.1
+	// package weights are rounded up and capacity is rounded down to it, DefaultWeightResolution when not specified
+	WeightResolution models.Weight
+	// Rule picking the packages of every shipment, MaxLoad when not specified
+	Strategy ShipmentStrategy
 }
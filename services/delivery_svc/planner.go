@@ -0,0 +1,234 @@
+package delivery_svc
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/delivery_utils"
+)
+
+// Shipments in the order of dispatch, each one picked by the strategy among the packages not shipped yet
+// weights are compared in whole units of the resolution, packages heavier than the capacity are not shipped
+func planShipments(items []*models.PackageDetails, maxWeight models.Weight, resolution models.Weight, strategy ShipmentStrategy) []models.Shipment {
+	planner := newShipmentPlanner(items, resolution, strategy)
+	var shipments []models.Shipment
+	for !planner.done() {
+		shipment := planner.next(maxWeight)
+		// nothing left the strategy would ship
+		if len(shipment) == 0 {
+			break
+		}
+		shipments = append(shipments, shipment)
+	}
+	return shipments
+}
+
+// Plans one shipment at a time, for the vehicle being dispatched (vehicles of a fleet can have different capacities)
+type shipmentPlanner struct {
+	items      []*models.PackageDetails // in the given order
+	pending    []PendingPackage         // not shipped yet, sorted by weight, then distance
+	shipped    map[*models.PackageDetails]bool
+	resolution models.Weight
+	strategy   ShipmentStrategy
+}
+
+func newShipmentPlanner(items []*models.PackageDetails, resolution models.Weight, strategy ShipmentStrategy) *shipmentPlanner {
+	pending := make([]PendingPackage, 0, len(items))
+	for _, item := range items {
+		weight := delivery_utils.WeightUnits(item.Weight, resolution)
+		// never part of a load
+		if weight <= 0 {
+			continue
+		}
+		pending = append(pending, PendingPackage{PackageDetails: item, Units: weight})
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		if pending[i].Weight == pending[j].Weight {
+			return pending[i].Distance < pending[j].Distance
+		}
+		return pending[i].Weight < pending[j].Weight
+	})
+	return &shipmentPlanner{items: items, pending: pending, shipped: make(map[*models.PackageDetails]bool), resolution: resolution, strategy: strategy}
+}
+
+// Every package is shipped
+func (p *shipmentPlanner) done() bool {
+	return len(p.pending) == 0
+}
+
+// Next shipment within the capacity (heaviest package first), empty when no pending package fits or the strategy picks none
+func (p *shipmentPlanner) next(maxWeight models.Weight) models.Shipment {
+	capacity := delivery_utils.CapacityUnits(maxWeight, p.resolution)
+
+	// strategies only see the packages fitting the capacity
+	var fitting []PendingPackage
+	for _, item := range p.pending {
+		if item.Units <= capacity {
+			fitting = append(fitting, item)
+		}
+	}
+	if len(fitting) == 0 {
+		return nil
+	}
+	picked := p.strategy.PickShipment(fitting, capacity)
+	// strategies are pluggable, a shipment beyond the capacity is never dispatched
+	if !withinCapacity(fitting, picked, capacity) {
+		picked = MaxLoad.PickShipment(fitting, capacity)
+	}
+
+	var shipment models.Shipment
+	for i := len(fitting) - 1; i >= 0; i-- {
+		if picked[i] {
+			shipment = append(shipment, fitting[i].PackageDetails)
+			p.shipped[fitting[i].PackageDetails] = true
+		}
+	}
+	remaining := p.pending[:0]
+	for _, item := range p.pending {
+		if !p.shipped[item.PackageDetails] {
+			remaining = append(remaining, item)
+		}
+	}
+	p.pending = remaining
+	return shipment
+}
+
+// Picks mark every pending package, and the picked load does not exceed the capacity
+func withinCapacity(pending []PendingPackage, picked []bool, capacity int) bool {
+	if len(picked) != len(pending) {
+		return false
+	}
+	load := 0
+	for i, item := range pending {
+		if picked[i] {
+			load += item.Units
+		}
+	}
+	return load <= capacity
+}
+
+// Packages not shipped so far, in the given order (ex: heavier than every vehicle, lighter than the resolution)
+func (p *shipmentPlanner) unshipped() []*models.PackageDetails {
+	var unshipped []*models.PackageDetails
+	for _, item := range p.items {
+		if !p.shipped[item] {
+			unshipped = append(unshipped, item)
+		}
+	}
+	return unshipped
+}
+
+// Packages of the heaviest load (within the capacity) among the sorted packages
+// Among loads of the same weight, lighter (so more) packages are preferred, then nearer ones
+//
+// Load j is reachable by the first i packages when bit j of reachable(i) is set, reachable(i) = reachable(i-1) | reachable(i-1) << weight(i).
+// Package i is picked (from the last one) when the heaviest load within the remaining capacity needs it,
+// that is when reachable(i-1) does not reach the same load. Only every √n-th bitset is kept while computing the heaviest load,
+// the others are recomputed a block at a time while picking, so memory is O(√n · capacity / 64) words.
+func pickShipment(items []PendingPackage, capacity int) []bool {
+	picked := make([]bool, len(items))
+	total := 0
+	for _, item := range items {
+		total += item.Units
+	}
+	// every package fits
+	if total <= capacity {
+		for i := range picked {
+			picked[i] = true
+		}
+		return picked
+	}
+
+	n := len(items)
+	width := capacity + 1
+	block := int(math.Ceil(math.Sqrt(float64(n))))
+	checkpoints := make([]bitset, 0, n/block+1)
+	reachable := newBitset(width)
+	reachable.set(0)
+	for i := 0; i < n; i++ {
+		if i%block == 0 {
+			checkpoints = append(checkpoints, reachable.clone())
+		}
+		reachable.orShifted(reachable, items[i].Units)
+	}
+
+	// reachable(start) ... reachable(start + block) of the block being picked from
+	blockBitsets := make([]bitset, block+1)
+	for i := range blockBitsets {
+		blockBitsets[i] = newBitset(width)
+	}
+
+	j := capacity
+	load := reachable.highestUpTo(j)
+	for b := len(checkpoints) - 1; b >= 0 && j > 0; b-- {
+		start := b * block
+		end := start + block
+		if end > n {
+			end = n
+		}
+		copy(blockBitsets[0], checkpoints[b])
+		for i := start; i < end; i++ {
+			copy(blockBitsets[i-start+1], blockBitsets[i-start])
+			blockBitsets[i-start+1].orShifted(blockBitsets[i-start], items[i].Units)
+		}
+		for i := end; i > start && j > 0; i-- {
+			// heaviest load within j without package i
+			without := blockBitsets[i-1-start].highestUpTo(j)
+			if without == load {
+				continue
+			}
+			picked[i-1] = true
+			j -= items[i-1].Units
+			load -= items[i-1].Units
+		}
+	}
+	return picked
+}
+
+// Set of loads (units), bit j is set when load j is reachable
+type bitset []uint64
+
+func newBitset(width int) bitset {
+	return make(bitset, (width+63)/64)
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << uint(i%64)
+}
+
+func (b bitset) clone() bitset {
+	return append(bitset(nil), b...)
+}
+
+// b = b | src << shift, loads beyond the width of b are dropped (b and src can be the same)
+func (b bitset) orShifted(src bitset, shift int) {
+	words, offset := shift/64, uint(shift%64)
+	for k := len(b) - 1; k >= words; k-- {
+		v := src[k-words] << offset
+		if offset > 0 && k-words-1 >= 0 {
+			v |= src[k-words-1] >> (64 - offset)
+		}
+		b[k] |= v
+	}
+}
+
+// Highest load up to j, loads are never empty (0 is always reachable)
+func (b bitset) highestUpTo(j int) int {
+	k := j / 64
+	if k >= len(b) {
+		k, j = len(b)-1, len(b)*64-1
+	}
+	word := b[k] & (uint64(2)<<uint(j%64) - 1)
+	for {
+		if word != 0 {
+			return k*64 + 63 - bits.LeadingZeros64(word)
+		}
+		k--
+		if k < 0 {
+			return 0
+		}
+		word = b[k]
+	}
+}
@@ -0,0 +1,265 @@
+package delivery_svc
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/quick"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/common_utils"
+	"github.com/lakshmaji/delivery-shell/utils/delivery_utils"
+)
+
+// Knapsack table planner, replaced by the bitset planner (kept as reference, results should be identical)
+func tablePlanShipments(items []*models.PackageDetails, maxWeight models.Weight, resolution models.Weight) []models.Shipment {
+	capacity := delivery_utils.CapacityUnits(maxWeight, resolution)
+	var shipments []models.Shipment
+	for len(items) > 0 {
+		buffer := pickItemByMaxNetWeight(items, capacity, resolution)
+		shipmentItems := getShipmentItems(items, buffer, capacity, resolution)
+
+		if len(shipmentItems) == 0 {
+			break
+		}
+		shipments = append(shipments, shipmentItems)
+
+		items = removeItems(items, shipmentItems)
+	}
+	return shipments
+}
+
+func pickItemByMaxNetWeight(items []*models.PackageDetails, maxWeight int, resolution models.Weight) [][]weightBuffer {
+	buffer := make([][]weightBuffer, len(items)+1)
+	for i := 0; i < len(buffer); i++ {
+		buffer[i] = make([]weightBuffer, maxWeight+1)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Weight == items[j].Weight {
+			return items[i].Distance < items[j].Distance
+		}
+		return items[i].Weight < items[j].Weight
+	})
+
+	for i := 1; i <= len(items); i++ {
+		weight := delivery_utils.WeightUnits(items[i-1].Weight, resolution)
+		for j := 1; j <= maxWeight; j++ {
+			if weight > j {
+				buffer[i][j] = buffer[i-1][j]
+			} else {
+				prevItem := buffer[i-1][j-weight]
+				filledWeight := prevItem.computedWeight + weight
+
+				buffer[i][j] = weightBuffer{
+					computedWeight: common_utils.MaxVal(filledWeight, buffer[i-1][j].computedWeight),
+				}
+			}
+		}
+	}
+
+	return buffer
+}
+
+func removeItems(items []*models.PackageDetails, shippedItems []*models.PackageDetails) []*models.PackageDetails {
+	for _, shippedItem := range shippedItems {
+		for i, item := range items {
+			if item.IsSamePackage(*shippedItem) {
+				items = append(items[:i], items[i+1:]...)
+				break
+			}
+		}
+	}
+	return items
+}
+
+func getShipmentItems(items []*models.PackageDetails, buffer [][]weightBuffer, maxWeight int, resolution models.Weight) []*models.PackageDetails {
+	var bag []*models.PackageDetails
+	i := len(items)
+	j := maxWeight
+
+	for i > 0 && j > 0 {
+		if buffer[i][j].computedWeight == buffer[i-1][j].computedWeight {
+			i--
+		} else {
+			bag = append(bag, items[i-1])
+			j -= delivery_utils.WeightUnits(items[i-1].Weight, resolution)
+			i--
+		}
+	}
+
+	return bag
+}
+
+type weightBuffer struct {
+	computedWeight int
+}
+
+// Package ids of every shipment
+func shipmentIds(shipments []models.Shipment) [][]models.PackageID {
+	ids := [][]models.PackageID{}
+	for _, shipment := range shipments {
+		shipmentIds := []models.PackageID{}
+		for _, item := range shipment {
+			shipmentIds = append(shipmentIds, item.Id)
+		}
+		ids = append(ids, shipmentIds)
+	}
+	return ids
+}
+
+func packages(details ...string) []*models.PackageDetails {
+	items := []*models.PackageDetails{}
+	for _, detail := range details {
+		item := &models.PackageDetails{}
+		fmt.Sscan(detail, &item.Id, &item.Weight, &item.Distance)
+		items = append(items, item)
+	}
+	return items
+}
+
+// Planners modify the given packages, each one gets its own copy
+func assertSamePlan(t *testing.T, items []*models.PackageDetails, maxWeight models.Weight, resolution models.Weight) bool {
+	t.Helper()
+	expected := shipmentIds(tablePlanShipments(append([]*models.PackageDetails{}, items...), maxWeight, resolution))
+	result := shipmentIds(planShipments(append([]*models.PackageDetails{}, items...), maxWeight, resolution, MaxLoad))
+	if !reflect.DeepEqual(result, expected) {
+		t.Logf("capacity %v, expected %v received %v", maxWeight, expected, result)
+		return false
+	}
+	return true
+}
+
+func TestPlanShipmentsSameAsTablePlanner(t *testing.T) {
+	tt := []struct {
+		name      string
+		items     []*models.PackageDetails
+		maxWeight models.Weight
+		expected  [][]models.PackageID
+	}{
+		{
+			name:      "README sample 1",
+			items:     packages("pkg1 5 5", "pkg2 15 5", "pkg3 10 100"),
+			maxWeight: 200,
+			expected:  [][]models.PackageID{{"pkg2", "pkg3", "pkg1"}},
+		},
+		{
+			name:      "README sample 2",
+			items:     packages("PKG1 50 30", "PKG2 75 125", "PKG3 175 100", "PKG4 110 60", "PKG5 155 95"),
+			maxWeight: 200,
+			expected:  [][]models.PackageID{{"PKG4", "PKG2"}, {"PKG3"}, {"PKG5"}, {"PKG1"}},
+		},
+		{
+			name:      "README sample 3",
+			items:     packages("PKG1 3 30", "PKG2 2 125", "PKG3 3 100", "PKG4 4 60", "PKG5 1 95", "PKG6 5 95", "PKG7 6 95"),
+			maxWeight: 6,
+			expected:  [][]models.PackageID{{"PKG1", "PKG2", "PKG5"}, {"PKG7"}, {"PKG6"}, {"PKG4"}, {"PKG3"}},
+		},
+		{
+			name:      "same weights, nearer first",
+			items:     packages("PKG1 3 10", "PKG2 3 5", "PKG3 3 10", "PKG4 3 5"),
+			maxWeight: 6,
+			expected:  [][]models.PackageID{{"PKG4", "PKG2"}, {"PKG3", "PKG1"}},
+		},
+		{
+			name:      "heavier than capacity and weightless",
+			items:     packages("PKG1 3 10", "PKG2 28 5", "PKG3 0 5"),
+			maxWeight: 5,
+			expected:  [][]models.PackageID{{"PKG1"}},
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			result := shipmentIds(planShipments(append([]*models.PackageDetails{}, test.items...), test.maxWeight, 1, MaxLoad))
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %v received %v", test.expected, result)
+			}
+			assertSamePlan(t, test.items, test.maxWeight, 1)
+		})
+	}
+
+	t.Run("random batches", func(t *testing.T) {
+		property := func(batch plannerBatch) bool {
+			return assertSamePlan(t, batch.Items, batch.Capacity, batch.Resolution)
+		}
+		if err := quick.Check(property, &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(2))}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("random batches beyond a bitset word", func(t *testing.T) {
+		// larger capacities and more packages than a single word (64 units) and block (√n) of the planner
+		r := rand.New(rand.NewSource(3))
+		for iteration := 0; iteration < 50; iteration++ {
+			items := []*models.PackageDetails{}
+			for i := 0; i < 20+r.Intn(40); i++ {
+				items = append(items, &models.PackageDetails{Id: models.PackageID(fmt.Sprintf("PKG%d", i+1)), Weight: float64(1 + r.Intn(150)), Distance: float64(r.Intn(200))})
+			}
+			if !assertSamePlan(t, items, float64(100+r.Intn(300)), 1) {
+				t.FailNow()
+			}
+		}
+	})
+}
+
+func TestBitset(t *testing.T) {
+	b := newBitset(200)
+	b.set(0)
+	b.orShifted(b, 70)
+	b.orShifted(b, 63)
+	b.orShifted(b, 64)
+	// 0, 63, 64, 70, 127, 133, 134, 197
+	tt := []struct {
+		upTo     int
+		expected int
+	}{{0, 0}, {62, 0}, {63, 63}, {64, 64}, {100, 70}, {127, 127}, {133, 133}, {150, 134}, {199, 197}, {500, 197}}
+	for _, test := range tt {
+		if result := b.highestUpTo(test.upTo); result != test.expected {
+			t.Errorf("highest up to %d, expected %d received %d", test.upTo, test.expected, result)
+		}
+	}
+}
+
+// Batch of packages (weights 0.5 kg - 50 kg), ex: 2 000 packages
+func benchmarkBatch(n int) []*models.PackageDetails {
+	r := rand.New(rand.NewSource(1))
+	items := make([]*models.PackageDetails, n)
+	for i := range items {
+		items[i] = &models.PackageDetails{Id: models.PackageID(fmt.Sprintf("PKG%d", i+1)), Weight: common_utils.ToFixed(0.5+r.Float64()*49.5, 1), Distance: float64(1 + r.Intn(200))}
+	}
+	return items
+}
+
+func BenchmarkPlanShipments(b *testing.B) {
+	tt := []struct {
+		name       string
+		packages   int
+		maxWeight  models.Weight
+		resolution models.Weight
+		table      bool // table planner is too slow (and large) beyond this
+	}{
+		{name: "200 kg, 50 packages", packages: 50, maxWeight: 200, resolution: 1, table: true},
+		{name: "200 kg, 50 packages at 0.01 kg", packages: 50, maxWeight: 200, resolution: 0.01, table: true},
+		{name: "1000 kg, 200 packages", packages: 200, maxWeight: 1000, resolution: 1, table: true},
+		{name: "10000 kg, 2000 packages", packages: 2000, maxWeight: 10000, resolution: 1},
+		{name: "10000 kg, 2000 packages at 0.1 kg", packages: 2000, maxWeight: 10000, resolution: 0.1},
+	}
+	for _, test := range tt {
+		items := benchmarkBatch(test.packages)
+		b.Run("bitset/"+test.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				planShipments(append([]*models.PackageDetails{}, items...), test.maxWeight, test.resolution, MaxLoad)
+			}
+		})
+		if !test.table {
+			continue
+		}
+		b.Run("table/"+test.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tablePlanShipments(append([]*models.PackageDetails{}, items...), test.maxWeight, test.resolution)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package delivery_svc
+
+import (
+	"sort"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+// Package waiting to be shipped, along with its weight in units of the weight resolution (never 0, never above the capacity)
+type PendingPackage struct {
+	*models.PackageDetails
+	Units int
+}
+
+// Rule picking the packages of every shipment (ex: maximum load, most packages)
+type ShipmentStrategy interface {
+	// Name of the strategy, as selected from the command line (ex: max-load)
+	Name() string
+	// Marks the packages of the next shipment, picked[i] for pending[i], the load should not exceed the capacity (units)
+	// pending packages are sorted by weight, then distance. Remaining packages are not shipped when nothing is picked
+	// picks of another length, or exceeding the capacity, are replaced by the max-load shipment
+	PickShipment(pending []PendingPackage, capacity int) []bool
+}
+
+var (
+	// Heaviest load, among loads of the same weight more packages and then nearer ones (default)
+	MaxLoad ShipmentStrategy = maxLoadStrategy{}
+	// Most packages, lightest packages first
+	MostPackages ShipmentStrategy = mostPackagesStrategy{}
+	// Heaviest packages first, lighter ones fill the remaining capacity
+	HeaviestFirst ShipmentStrategy = heaviestFirstStrategy{}
+	// Nearest packages first, up to the first one not fitting (so farther packages do not lengthen the trip), shortens the average delivery time
+	NearestFirst ShipmentStrategy = nearestFirstStrategy{}
+	// Farthest packages first and together, so the longest trips start earliest and are not repeated (shortens the latest delivery time)
+	MinLatestDelivery ShipmentStrategy = minLatestDeliveryStrategy{}
+)
+
+// Built-in strategies, default one first
+func ShipmentStrategies() []ShipmentStrategy {
+	return []ShipmentStrategy{MaxLoad, MostPackages, HeaviestFirst, NearestFirst, MinLatestDelivery}
+}
+
+// Built-in strategy of the given name (ex: nearest-first)
+func ShipmentStrategyByName(name string) (ShipmentStrategy, error) {
+	var names []string
+	for _, strategy := range ShipmentStrategies() {
+		if strategy.Name() == name {
+			return strategy, nil
+		}
+		names = append(names, strategy.Name())
+	}
+	return nil, error_utils.ErrShipmentStrategy(name, names)
+}
+
+type maxLoadStrategy struct{}
+
+func (maxLoadStrategy) Name() string {
+	return "max-load"
+}
+
+func (maxLoadStrategy) PickShipment(pending []PendingPackage, capacity int) []bool {
+	return pickShipment(pending, capacity)
+}
+
+type mostPackagesStrategy struct{}
+
+func (mostPackagesStrategy) Name() string {
+	return "most-packages"
+}
+
+func (mostPackagesStrategy) PickShipment(pending []PendingPackage, capacity int) []bool {
+	return pickInOrder(pending, capacity, func(a, b PendingPackage) bool {
+		return a.Units < b.Units
+	})
+}
+
+type heaviestFirstStrategy struct{}
+
+func (heaviestFirstStrategy) Name() string {
+	return "heaviest-first"
+}
+
+func (heaviestFirstStrategy) PickShipment(pending []PendingPackage, capacity int) []bool {
+	return pickInOrder(pending, capacity, func(a, b PendingPackage) bool {
+		if a.Units == b.Units {
+			return a.Distance < b.Distance
+		}
+		return a.Units > b.Units
+	})
+}
+
+type nearestFirstStrategy struct{}
+
+func (nearestFirstStrategy) Name() string {
+	return "nearest-first"
+}
+
+func (nearestFirstStrategy) PickShipment(pending []PendingPackage, capacity int) []bool {
+	picked := make([]bool, len(pending))
+	for _, i := range orderBy(pending, func(a, b PendingPackage) bool {
+		return a.Distance < b.Distance
+	}) {
+		if pending[i].Units > capacity {
+			break
+		}
+		picked[i] = true
+		capacity -= pending[i].Units
+	}
+	return picked
+}
+
+type minLatestDeliveryStrategy struct{}
+
+func (minLatestDeliveryStrategy) Name() string {
+	return "min-latest-delivery"
+}
+
+func (minLatestDeliveryStrategy) PickShipment(pending []PendingPackage, capacity int) []bool {
+	return pickInOrder(pending, capacity, func(a, b PendingPackage) bool {
+		if a.Distance == b.Distance {
+			return a.Units > b.Units
+		}
+		return a.Distance > b.Distance
+	})
+}
+
+// Picks packages in the given order (pending order on ties), skipping the ones no longer fitting the remaining capacity
+func pickInOrder(pending []PendingPackage, capacity int, less func(a, b PendingPackage) bool) []bool {
+	picked := make([]bool, len(pending))
+	for _, i := range orderBy(pending, less) {
+		if pending[i].Units <= capacity {
+			picked[i] = true
+			capacity -= pending[i].Units
+		}
+	}
+	return picked
+}
+
+// Indexes of the pending packages in the given order, pending order on ties
+func orderBy(pending []PendingPackage, less func(a, b PendingPackage) bool) []int {
+	order := make([]int, len(pending))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(pending[order[i]], pending[order[j]])
+	})
+	return order
+}
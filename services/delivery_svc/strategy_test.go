@@ -0,0 +1,198 @@
+package delivery_svc
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/delivery_utils"
+)
+
+func TestShipmentStrategyByName(t *testing.T) {
+	for _, strategy := range ShipmentStrategies() {
+		t.Run(strategy.Name(), func(t *testing.T) {
+			result, err := ShipmentStrategyByName(strategy.Name())
+			if err != nil || result != strategy {
+				t.Errorf("expected %s received %v, %v", strategy.Name(), result, err)
+			}
+		})
+	}
+
+	_, err := ShipmentStrategyByName("fastest")
+	expected := `Shipment strategy should be one of max-load, most-packages, heaviest-first, nearest-first, min-latest-delivery, received "fastest"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected %s received %v", expected, err)
+	}
+}
+
+func TestShipmentStrategies(t *testing.T) {
+	// 2 vehicles, 70 km/hr, 200 kg
+	items := packages("PKG1 10 105", "PKG2 15 195", "PKG3 75 85", "PKG4 115 120", "PKG5 60 80", "PKG6 10 200", "PKG7 145 25")
+
+	tt := []struct {
+		strategy  ShipmentStrategy
+		shipments [][]models.PackageID
+		want      models.PackageDeliveryTime
+	}{
+		{
+			strategy:  MaxLoad,
+			shipments: [][]models.PackageID{{"PKG4", "PKG5", "PKG2", "PKG1"}, {"PKG7", "PKG6"}, {"PKG3"}},
+			want:      models.PackageDeliveryTime{"PKG1": 1.5, "PKG2": 2.78, "PKG3": 6.77, "PKG4": 1.71, "PKG5": 1.14, "PKG6": 2.85, "PKG7": 0.35},
+		},
+		{
+			strategy:  MostPackages,
+			shipments: [][]models.PackageID{{"PKG3", "PKG5", "PKG2", "PKG6", "PKG1"}, {"PKG4"}, {"PKG7"}},
+			want:      models.PackageDeliveryTime{"PKG1": 1.5, "PKG2": 2.78, "PKG3": 1.21, "PKG4": 1.71, "PKG5": 1.14, "PKG6": 2.85, "PKG7": 3.77},
+		},
+		{
+			strategy:  HeaviestFirst,
+			shipments: [][]models.PackageID{{"PKG7", "PKG2", "PKG6", "PKG1"}, {"PKG4", "PKG3"}, {"PKG5"}},
+			want:      models.PackageDeliveryTime{"PKG1": 1.5, "PKG2": 2.78, "PKG3": 1.21, "PKG4": 1.71, "PKG5": 4.56, "PKG6": 2.85, "PKG7": 0.35},
+		},
+		{
+			strategy:  NearestFirst,
+			shipments: [][]models.PackageID{{"PKG7"}, {"PKG3", "PKG5", "PKG1"}, {"PKG4", "PKG2", "PKG6"}},
+			want:      models.PackageDeliveryTime{"PKG1": 1.5, "PKG2": 3.48, "PKG3": 1.21, "PKG4": 2.41, "PKG5": 1.14, "PKG6": 3.55, "PKG7": 0.35},
+		},
+		{
+			strategy:  MinLatestDelivery,
+			shipments: [][]models.PackageID{{"PKG4", "PKG2", "PKG6", "PKG1"}, {"PKG3", "PKG5"}, {"PKG7"}},
+			want:      models.PackageDeliveryTime{"PKG1": 1.5, "PKG2": 2.78, "PKG3": 1.21, "PKG4": 1.71, "PKG5": 1.14, "PKG6": 2.85, "PKG7": 2.77},
+		},
+	}
+
+	firstShipments := make(map[ShipmentStrategy]models.Shipment)
+	deliveryTimes := make(map[ShipmentStrategy]models.PackageDeliveryTime)
+	for _, test := range tt {
+		t.Run(test.strategy.Name(), func(t *testing.T) {
+			shipments := planShipments(append([]*models.PackageDetails{}, items...), 200, 1, test.strategy)
+			if result := shipmentIds(shipments); !reflect.DeepEqual(result, test.shipments) {
+				t.Errorf("expected shipments %v received %v", test.shipments, result)
+			}
+			firstShipments[test.strategy] = shipments[0]
+
+			svc := NewDeliveryServiceWithOptions(NewOffersSvcMock(), models.DefaultRateCard(), Options{Strategy: test.strategy})
+			got := svc.EstDeliveryTime(append([]*models.PackageDetails{}, items...), models.NewFleet(2, 70, 200))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("EstDeliveryTime() = %v, want %v", got, test.want)
+			}
+			deliveryTimes[test.strategy] = got
+		})
+	}
+
+	// every strategy is the best one of the batch at its own goal
+	goals := []struct {
+		strategy ShipmentStrategy
+		goal     string
+		score    func(strategy ShipmentStrategy) float64 // higher is better
+	}{
+		{strategy: MaxLoad, goal: "load of the first shipment", score: func(strategy ShipmentStrategy) float64 {
+			return totalWeight(firstShipments[strategy])
+		}},
+		{strategy: MostPackages, goal: "packages of the first shipment", score: func(strategy ShipmentStrategy) float64 {
+			return float64(len(firstShipments[strategy]))
+		}},
+		{strategy: NearestFirst, goal: "average delivery time", score: func(strategy ShipmentStrategy) float64 {
+			total := 0.0
+			for _, deliveryTime := range deliveryTimes[strategy] {
+				total += deliveryTime
+			}
+			return -total / float64(len(deliveryTimes[strategy]))
+		}},
+		{strategy: MinLatestDelivery, goal: "latest delivery time", score: func(strategy ShipmentStrategy) float64 {
+			latest := 0.0
+			for _, deliveryTime := range deliveryTimes[strategy] {
+				if deliveryTime > latest {
+					latest = deliveryTime
+				}
+			}
+			return -latest
+		}},
+	}
+	for _, goal := range goals {
+		for _, test := range tt {
+			if test.strategy != goal.strategy && goal.score(test.strategy) >= goal.score(goal.strategy) {
+				t.Errorf("%s: expected %s to be better than %s", goal.goal, goal.strategy.Name(), test.strategy.Name())
+			}
+		}
+	}
+	if firstShipments[HeaviestFirst][0].Id != "PKG7" {
+		t.Errorf("expected heaviest package PKG7 to be shipped first, received %v", firstShipments[HeaviestFirst][0].Id)
+	}
+}
+
+func TestShipmentStrategiesProperties(t *testing.T) {
+	for _, strategy := range ShipmentStrategies() {
+		t.Run(strategy.Name(), func(t *testing.T) {
+			property := func(batch plannerBatch) bool {
+				capacity := delivery_utils.CapacityUnits(batch.Capacity, batch.Resolution)
+				shipped := make(map[models.PackageID]int)
+				for _, shipment := range planShipments(append([]*models.PackageDetails{}, batch.Items...), batch.Capacity, batch.Resolution, strategy) {
+					load := 0
+					for _, item := range shipment {
+						load += delivery_utils.WeightUnits(item.Weight, batch.Resolution)
+						shipped[item.Id]++
+					}
+					if load > capacity {
+						t.Logf("shipment of %d units exceeds capacity %d units", load, capacity)
+						return false
+					}
+				}
+				for _, item := range batch.Items {
+					weight := delivery_utils.WeightUnits(item.Weight, batch.Resolution)
+					fits := weight > 0 && weight <= capacity
+					if (fits && shipped[item.Id] != 1) || (!fits && shipped[item.Id] != 0) {
+						t.Logf("package %s of %v kg shipped %d times", item.Id, item.Weight, shipped[item.Id])
+						return false
+					}
+				}
+				return true
+			}
+			if err := quick.Check(property, &quick.Config{MaxCount: 300, Rand: rand.New(rand.NewSource(1))}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Strategy which does not play by the rules
+type brokenStrategy struct {
+	name string
+	pick func(pending []PendingPackage, capacity int) []bool
+}
+
+func (s brokenStrategy) Name() string {
+	return s.name
+}
+
+func (s brokenStrategy) PickShipment(pending []PendingPackage, capacity int) []bool {
+	return s.pick(pending, capacity)
+}
+
+func TestBrokenShipmentStrategies(t *testing.T) {
+	items := packages("PKG1 50 30", "PKG2 75 125", "PKG3 175 100", "PKG4 110 60", "PKG5 155 95")
+	expected := shipmentIds(planShipments(append([]*models.PackageDetails{}, items...), 200, 1, MaxLoad))
+
+	for _, strategy := range []ShipmentStrategy{
+		brokenStrategy{name: "short", pick: func(pending []PendingPackage, capacity int) []bool {
+			return []bool{true}
+		}},
+		brokenStrategy{name: "overfill", pick: func(pending []PendingPackage, capacity int) []bool {
+			picked := make([]bool, len(pending))
+			for i := range picked {
+				picked[i] = true
+			}
+			return picked
+		}},
+	} {
+		t.Run(strategy.Name(), func(t *testing.T) {
+			// falls back to max load, instead of panicking or exceeding the capacity
+			result := shipmentIds(planShipments(append([]*models.PackageDetails{}, items...), 200, 1, strategy))
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("expected shipments %v received %v", expected, result)
+			}
+		})
+	}
+}
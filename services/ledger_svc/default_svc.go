@@ -0,0 +1,145 @@
+package ledger_svc
+
+import (
+	"sync"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+// Redemptions of an offer
+type usage struct {
+	Total     int                       `json:"total"`
+	Customers map[models.CustomerID]int `json:"customers,omitempty"`
+}
+
+type memoryLedger struct {
+	mu        sync.Mutex
+	committed map[models.OfferCode]*usage
+	reserved  map[int64]Reservation
+	lastID    int64
+	// persists committed redemptions, nil keeps them in memory only
+	persist func(map[models.OfferCode]*usage) error
+}
+
+// Ledger which keeps redemptions in memory, for the lifetime of the process
+func NewMemoryLedger() RedemptionLedger {
+	return newMemoryLedger(nil, nil)
+}
+
+func newMemoryLedger(committed map[models.OfferCode]*usage, persist func(map[models.OfferCode]*usage) error) *memoryLedger {
+	if committed == nil {
+		committed = make(map[models.OfferCode]*usage)
+	}
+	return &memoryLedger{
+		committed: committed,
+		reserved:  make(map[int64]Reservation),
+		persist:   persist,
+	}
+}
+
+func (l *memoryLedger) Reserve(code models.OfferCode, customer models.CustomerID, limits Limits) (Reservation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limits.PerCustomer > 0 && customer == "" {
+		return Reservation{}, error_utils.ErrCustomerRequired
+	}
+	total, byCustomer := l.redemptions(code, customer)
+	if limits.Total > 0 && total >= limits.Total {
+		return Reservation{}, error_utils.ErrRedemptionLimit
+	}
+	if limits.PerCustomer > 0 && byCustomer >= limits.PerCustomer {
+		return Reservation{}, error_utils.ErrCustomerLimit
+	}
+	l.lastID++
+	reservation := Reservation{ID: l.lastID, Code: code, Customer: customer}
+	l.reserved[reservation.ID] = reservation
+	return reservation, nil
+}
+
+func (l *memoryLedger) Cancel(reservation Reservation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.reserved, reservation.ID)
+}
+
+// Reservations are kept when committed redemptions can not be persisted, so that they can be released
+func (l *memoryLedger) Commit() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	committed := make(map[models.OfferCode]*usage, len(l.committed))
+	for code, u := range l.committed {
+		committed[code] = u.clone()
+	}
+	for _, reservation := range l.reserved {
+		u, ok := committed[reservation.Code]
+		if !ok {
+			u = &usage{}
+			committed[reservation.Code] = u
+		}
+		u.add(reservation.Customer)
+	}
+	if l.persist != nil {
+		if err := l.persist(committed); err != nil {
+			return err
+		}
+	}
+	l.committed = committed
+	l.reserved = make(map[int64]Reservation)
+	return nil
+}
+
+func (l *memoryLedger) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reserved = make(map[int64]Reservation)
+}
+
+func (l *memoryLedger) Redemptions(code models.OfferCode, customer models.CustomerID) (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.redemptions(code, customer)
+}
+
+func (l *memoryLedger) redemptions(code models.OfferCode, customer models.CustomerID) (total int, byCustomer int) {
+	if u, ok := l.committed[code]; ok {
+		total = u.Total
+		if customer != "" {
+			byCustomer = u.Customers[customer]
+		}
+	}
+	for _, reservation := range l.reserved {
+		if reservation.Code != code {
+			continue
+		}
+		total++
+		if customer != "" && reservation.Customer == customer {
+			byCustomer++
+		}
+	}
+	return total, byCustomer
+}
+
+func (u *usage) add(customer models.CustomerID) {
+	u.Total++
+	if customer == "" {
+		return
+	}
+	if u.Customers == nil {
+		u.Customers = make(map[models.CustomerID]int)
+	}
+	u.Customers[customer]++
+}
+
+func (u *usage) clone() *usage {
+	c := &usage{Total: u.Total}
+	if u.Customers != nil {
+		c.Customers = make(map[models.CustomerID]int, len(u.Customers))
+		for customer, count := range u.Customers {
+			c.Customers[customer] = count
+		}
+	}
+	return c
+}
@@ -0,0 +1,65 @@
+package ledger_svc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+func TestReserve(t *testing.T) {
+	ledger := NewMemoryLedger()
+	limits := Limits{Total: 3, PerCustomer: 2}
+
+	for i := 0; i < 2; i++ {
+		if _, err := ledger.Reserve("A", "C1", limits); err != nil {
+			t.Fatalf("should not throw error, received %v", err)
+		}
+	}
+	if _, err := ledger.Reserve("A", "C1", limits); !errors.Is(err, error_utils.ErrCustomerLimit) {
+		t.Errorf("Reserve() error = %v, want %v", err, error_utils.ErrCustomerLimit)
+	}
+	if _, err := ledger.Reserve("A", "", limits); !errors.Is(err, error_utils.ErrCustomerRequired) {
+		t.Errorf("Reserve() error = %v, want %v", err, error_utils.ErrCustomerRequired)
+	}
+	if _, err := ledger.Reserve("A", "C2", limits); err != nil {
+		t.Errorf("should not throw error, received %v", err)
+	}
+	if _, err := ledger.Reserve("A", "C3", limits); !errors.Is(err, error_utils.ErrRedemptionLimit) {
+		t.Errorf("Reserve() error = %v, want %v (total)", err, error_utils.ErrRedemptionLimit)
+	}
+	// other offers and unlimited offers are not affected
+	if _, err := ledger.Reserve("B", "", Limits{}); err != nil {
+		t.Errorf("should not throw error, received %v", err)
+	}
+
+	if total, byCustomer := ledger.Redemptions("A", "C1"); total != 3 || byCustomer != 2 {
+		t.Errorf("Redemptions() = %d, %d, want 3, 2", total, byCustomer)
+	}
+}
+
+func TestCommitAndRelease(t *testing.T) {
+	ledger := NewMemoryLedger()
+	limits := Limits{Total: 2}
+
+	first, _ := ledger.Reserve("A", "C1", limits)
+	if _, err := ledger.Reserve("A", "C2", limits); err != nil {
+		t.Fatal(err)
+	}
+	ledger.Cancel(first)
+	if total, _ := ledger.Redemptions("A", ""); total != 1 {
+		t.Errorf("Redemptions() = %d, want 1 (cancelled reservation)", total)
+	}
+	if err := ledger.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// failed run
+	if _, err := ledger.Reserve("A", "C1", limits); err != nil {
+		t.Fatal(err)
+	}
+	ledger.Release()
+	if total, byCustomer := ledger.Redemptions("A", "C2"); total != 1 || byCustomer != 1 {
+		t.Errorf("Redemptions() = %d, %d, want 1, 1 (committed only)", total, byCustomer)
+	}
+}
@@ -0,0 +1,56 @@
+package ledger_svc
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+// Ledger which keeps committed redemptions in a json file, so that limits hold across runs
+// A missing file is an empty ledger, the file is replaced as a whole on every commit
+//
+//	{"OFR001": {"total": 3, "customers": {"CUST1": 2}}}
+func NewFileLedger(filename string) (RedemptionLedger, error) {
+	committed := make(map[models.OfferCode]*usage)
+	content, err := os.ReadFile(filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, error_utils.ErrLedger(filename, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, &committed); err != nil {
+			return nil, error_utils.ErrLedger(filename, err)
+		}
+	}
+	return newMemoryLedger(committed, func(committed map[models.OfferCode]*usage) error {
+		return writeLedger(filename, committed)
+	}), nil
+}
+
+// Written aside and renamed, so that a failed write never leaves a partial ledger behind
+func writeLedger(filename string, committed map[models.OfferCode]*usage) error {
+	content, err := json.MarshalIndent(committed, "", "  ")
+	if err != nil {
+		return error_utils.ErrLedger(filename, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return error_utils.ErrLedger(filename, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return error_utils.ErrLedger(filename, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return error_utils.ErrLedger(filename, err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return error_utils.ErrLedger(filename, err)
+	}
+	return nil
+}
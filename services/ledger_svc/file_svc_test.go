@@ -0,0 +1,74 @@
+package ledger_svc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+func TestFileLedger(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "redemptions.json")
+	limits := Limits{Total: 2, PerCustomer: 1}
+
+	ledger, err := NewFileLedger(filename)
+	if err != nil {
+		t.Fatalf("missing file should be an empty ledger, received %v", err)
+	}
+	if _, err := ledger.Reserve("A", "C1", limits); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filename); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("reservations should not be persisted until committed, received %v", err)
+	}
+	if err := ledger.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// next run
+	ledger, err = NewFileLedger(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total, byCustomer := ledger.Redemptions("A", "C1"); total != 1 || byCustomer != 1 {
+		t.Errorf("Redemptions() = %d, %d, want 1, 1", total, byCustomer)
+	}
+	if _, err := ledger.Reserve("A", "C1", limits); !errors.Is(err, error_utils.ErrCustomerLimit) {
+		t.Errorf("Reserve() error = %v, want %v", err, error_utils.ErrCustomerLimit)
+	}
+}
+
+func TestFileLedgerFail(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "redemptions.json")
+	if err := os.WriteFile(filename, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileLedger(filename); err == nil {
+		t.Error("Should throw error")
+	}
+
+	// directory of the ledger is gone
+	dir := filepath.Join(t.TempDir(), "ledger")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ledger, err := NewFileLedger(filepath.Join(dir, "redemptions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ledger.Reserve("A", "", Limits{Total: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.Commit(); err == nil {
+		t.Error("Should throw error")
+	}
+	// reservation is kept, it can still be released
+	if total, _ := ledger.Redemptions("A", ""); total != 1 {
+		t.Errorf("Redemptions() = %d, want 1", total)
+	}
+}
@@ -0,0 +1,32 @@
+package ledger_svc
+
+import "github.com/lakshmaji/delivery-shell/models"
+
+// Pending redemption of an offer, until it is committed or released
+type Reservation struct {
+	ID       int64
+	Code     models.OfferCode
+	Customer models.CustomerID
+}
+
+// Redemption limits of an offer, 0 is unlimited
+type Limits struct {
+	Total       int
+	PerCustomer int
+}
+
+// Tracks offer redemptions, so that offer usage limits can be enforced across packages and runs
+// Redemptions are reserved while discounts are granted, and committed (or released) at the end of a run
+type RedemptionLedger interface {
+	// Reserves a redemption of the offer for the customer, committed and reserved redemptions count towards the limits
+	// returns ErrRedemptionLimit (total) or ErrCustomerLimit when a limit is reached, ErrCustomerRequired for per customer limit without customer
+	Reserve(code models.OfferCode, customer models.CustomerID, limits Limits) (Reservation, error)
+	// Drops a single reservation (ex: discount which is not combined with the applied ones)
+	Cancel(reservation Reservation)
+	// Turns every reservation into a redemption
+	Commit() error
+	// Drops every reservation (ex: run failed partway)
+	Release()
+	// Committed and reserved redemptions of the offer, in total and by the customer
+	Redemptions(code models.OfferCode, customer models.CustomerID) (total int, byCustomer int)
+}
@@ -1,50 +1,362 @@
 package offers_svc
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lakshmaji/delivery-shell/clients"
 	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/services/ledger_svc"
+	"github.com/lakshmaji/delivery-shell/services/store_svc"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+	"github.com/lakshmaji/delivery-shell/utils/msg_utils"
 	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
 )
 
+// Offers indexed by offer code
+type offerIndex struct {
+	offers []models.Offer
+	byCode map[models.OfferCode]models.Offer
+	codes  []models.OfferCode // in order of definition, without duplicates
+}
+
+func newOfferIndex(offers []models.Offer) *offerIndex {
+	index := &offerIndex{
+		offers: offers,
+		byCode: make(map[models.OfferCode]models.Offer, len(offers)),
+	}
+	for _, offer := range offers {
+		if _, ok := index.byCode[offer.Code]; !ok {
+			index.codes = append(index.codes, offer.Code)
+		}
+		index.byCode[offer.Code] = offer
+	}
+	return index
+}
+
 type offerService struct {
-	fn func(filename string) ([]models.Offer, error)
+	store     store_svc.OfferStore
+	rounding  models.RoundingMode
+	clock     Clock
+	autoApply bool
+	strict    bool
+	ledger    ledger_svc.RedemptionLedger
+
+	mu      sync.Mutex   // serializes reloads
+	current atomic.Value // *offerIndex, swapped as a whole on reload
 }
 
 // Offers service which works with a local json file
-func NewOffersService(fn func(string) ([]models.Offer, error)) OffersService {
+// Offers are loaded from the given file (using fn) on first use and kept in memory, until reloaded
+// Discounts are rounded using the given rounding mode
+func NewOffersService(fn func(string) ([]models.Offer, error), filename string, rounding models.RoundingMode) ReloadableOffersService {
+	return NewOffersServiceWithOptions(fn, filename, rounding, Options{})
+}
+
+// Offers service with optional behaviour (clock, auto apply, strict, redemption ledger)
+func NewOffersServiceWithOptions(fn func(string) ([]models.Offer, error), filename string, rounding models.RoundingMode, options Options) ReloadableOffersService {
+	return NewOffersServiceWithStore(store_svc.NewFileStore(filename, fn), rounding, options)
+}
+
+// Offers service which works with any offer store (file, SQLite, http etc.)
+func NewOffersServiceWithStore(store store_svc.OfferStore, rounding models.RoundingMode, options Options) ReloadableOffersService {
+	if options.Clock == nil {
+		options.Clock = time.Now
+	}
+	if options.Ledger == nil {
+		options.Ledger = ledger_svc.NewMemoryLedger()
+	}
 	return &offerService{
-		fn: fn,
+		store:     store,
+		rounding:  rounding,
+		clock:     options.Clock,
+		autoApply: options.AutoApply,
+		strict:    options.Strict,
+		ledger:    options.Ledger,
+	}
+}
+
+// Active offers, loads them when not yet loaded
+func (o *offerService) load() (*offerIndex, error) {
+	if index, ok := o.current.Load().(*offerIndex); ok {
+		return index, nil
+	}
+	if err := o.Reload(); err != nil {
+		return nil, err
+	}
+	return o.current.Load().(*offerIndex), nil
+}
+
+// Loads (and validates) offers, the active offers are replaced only when the new offers are valid
+func (o *offerService) Reload() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	offers, err := o.store.List()
+	if err != nil {
+		return err
+	}
+	o.current.Store(newOfferIndex(offers))
+	return nil
+}
+
+// Polls the offer store for changes (in the background) and reloads offers when they are modified, until the context is done.
+// The current revision is read before returning, so every change made afterwards is noticed.
+// Outcome of every reload is reported using the writer.
+func (o *offerService) Watch(ctx context.Context, interval time.Duration, writer clients.BaseWriter) {
+	go o.poll(ctx, interval, writer, o.version())
+}
+
+func (o *offerService) poll(ctx context.Context, interval time.Duration, writer clients.BaseWriter, lastVersion string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			version := o.version()
+			if version == lastVersion {
+				continue
+			}
+			lastVersion = version
+			if err := o.Reload(); err != nil {
+				writer.Write(fmt.Sprintf(msg_utils.MsgOffersReloadFailed, o.store.Source(), err))
+				continue
+			}
+			index, _ := o.current.Load().(*offerIndex)
+			writer.Write(fmt.Sprintf(msg_utils.MsgOffersReloaded, o.store.Source(), len(index.offers)))
+		}
+	}
+}
+
+// Revision of the offers, an unavailable store is a revision too (so that recovery is noticed)
+func (o *offerService) version() string {
+	version, err := o.store.Version()
+	if err != nil {
+		return err.Error()
+	}
+	return version
+}
+
+// Evaluates the offer of the given code, applied result carries the discount of the offer alone
+func (o *offerService) evaluate(index *offerIndex, deliveryCost models.Money, code models.OfferCode, fact models.Fact, now time.Time) (models.Offer, models.DiscountResult) {
+	offer, ok := index.byCode[code]
+	if !ok {
+		return offer, missingOffer(index, code)
+	}
+	result := models.DiscountResult{Code: code, Status: models.DiscountNotApplicable}
+	if active, status, reason := offer_utils.IsActive(offer, now); !active {
+		result.Status, result.Reason = status, reason
+		return offer, result
+	}
+
+	fact.Code = code
+	if !offer_utils.IsApplicable(offer, fact) {
+		result.Reason = "conditions are not satisfied"
+		return offer, result
 	}
+	result.Status = models.DiscountApplied
+	result.Amount = offer_utils.DiscountAmount(offer, deliveryCost, o.rounding)
+	return offer, result
 }
 
-// Retrieves the offer object for a given offer-code
-func (o *offerService) retrieveOfferBy(code models.OfferCode) (models.Offer, error) {
-	OffersSlice, err := o.fn("offers.json")
+func (o *offerService) ApplicableDiscount(deliveryCost models.Money, code models.OfferCode, fact models.Fact) (models.DiscountResult, error) {
+	// a single offer set is used for the whole call, even when offers are reloaded meanwhile
+	index, err := o.load()
 	if err != nil {
-		return models.Offer{}, err
+		return models.DiscountResult{Code: code, Status: models.DiscountNotApplicable}, err
 	}
-	// convert OffersSlice to map with offer.Code as key
-	offersMap := make(map[models.OfferCode]models.Offer)
-	for _, offer := range OffersSlice {
-		offersMap[offer.Code] = offer
+	if err := o.checkUnknown(index, []models.OfferCode{code}); err != nil {
+		return missingOffer(index, code), err
 	}
+	offer, result := o.evaluate(index, deliveryCost, code, fact, o.clock())
+	result, _ = o.reserve(offer, result, fact.Customer)
+	return result, nil
+}
+
+func (o *offerService) ApplicableDiscounts(deliveryCost models.Money, codes []models.OfferCode, fact models.Fact) ([]models.DiscountResult, error) {
+	index, err := o.load()
+	if err != nil {
+		return nil, err
+	}
+	now := o.clock()
+
+	// nothing is reserved when a code is unknown (strict)
+	if err := o.checkUnknown(index, codes); err != nil {
+		return nil, err
+	}
+	results := make([]models.DiscountResult, len(codes))
 
-	return offersMap[code], nil
+	applicable := []models.Offer{}
+	reservations := []ledger_svc.Reservation{}
+	positions := []int{}
+	for i, code := range codes {
+		offer, result := o.evaluate(index, deliveryCost, code, fact, now)
+		result, reservation := o.reserve(offer, result, fact.Customer)
+		results[i] = result
+		if result.IsApplied() {
+			applicable = append(applicable, offer)
+			reservations = append(reservations, reservation)
+			positions = append(positions, i)
+		}
+	}
+	for i, result := range offer_utils.StackOffers(applicable, deliveryCost, o.rounding) {
+		results[positions[i]] = result
+		// discounts which are not combined are not redeemed
+		if !result.IsApplied() {
+			o.ledger.Cancel(reservations[i])
+		}
+	}
+
+	if o.autoApply && !hasKnownCode(index, codes) {
+		if result, ok := o.bestOffer(index, deliveryCost, fact, now); ok {
+			results = append(results, result)
+		}
+	}
+	return results, nil
 }
 
-func (o *offerService) ApplicableDiscount(deliveryCost float64, code models.OfferCode, wt models.Weight, dt models.Distance) (float64, error) {
-	// faking our local database call
-	offer, err := o.retrieveOfferBy(code)
+func (o *offerService) Explain(codes []models.OfferCode, fact models.Fact, results []models.DiscountResult) ([]models.OfferExplanation, error) {
+	index, err := o.load()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	fact := models.Fact{
-		Weight:   wt,
-		Distance: dt,
+	now := o.clock()
+
+	explanations := make([]models.OfferExplanation, 0, len(codes))
+	for _, code := range codes {
+		offer, ok := index.byCode[code]
+		if !ok {
+			missing := missingOffer(index, code)
+			explanations = append(explanations, models.OfferExplanation{Code: code, Status: missing.Status, Reason: missing.Reason, Suggestion: missing.Suggestion})
+			continue
+		}
+		explanation := models.OfferExplanation{Code: code, Found: true, Status: models.DiscountNotApplicable}
+
+		fact.Code = code
+		explanation.Conditions = offer_utils.ExplainConditions(offer, fact)
+		if active, status, reason := offer_utils.IsActive(offer, now); !active {
+			explanation.Status, explanation.Reason = status, reason
+		} else if offer_utils.IsApplicable(offer, fact) {
+			explanation.Status = models.DiscountApplied
+			// nothing is redeemed while explaining, the limit is the one met when the discount was granted
+			for _, result := range results {
+				if result.Code == code && result.Status == models.DiscountLimitReached {
+					explanation.Status, explanation.Reason = result.Status, result.Reason
+				}
+			}
+		} else {
+			explanation.Reason = "conditions are not satisfied"
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations, nil
+}
+
+// Active offer (opted in to auto apply) with the highest discount for the package, first one on a tie
+// offers whose redemption limit is reached are skipped
+func (o *offerService) bestOffer(index *offerIndex, deliveryCost models.Money, fact models.Fact, now time.Time) (models.DiscountResult, bool) {
+	candidates := []models.Offer{}
+	amounts := []models.DiscountResult{}
+	for _, offer := range index.offers {
+		// the last definition of a duplicate code is the one in effect
+		if !index.byCode[offer.Code].IsAutoApplicable() {
+			continue
+		}
+		offer, result := o.evaluate(index, deliveryCost, offer.Code, fact, now)
+		if result.IsApplied() {
+			candidates = append(candidates, offer)
+			amounts = append(amounts, result)
+		}
+	}
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return amounts[order[i]].Amount > amounts[order[j]].Amount })
+	for _, i := range order {
+		if result, _ := o.reserve(candidates[i], amounts[i], fact.Customer); result.IsApplied() {
+			result.AutoApplied = true
+			return result, true
+		}
+	}
+	return models.DiscountResult{}, false
+}
+
+// Reserves a redemption for the granted discount, the discount is not applied when the limit of the offer is reached
+func (o *offerService) reserve(offer models.Offer, result models.DiscountResult, customer models.CustomerID) (models.DiscountResult, ledger_svc.Reservation) {
+	if !result.IsApplied() {
+		return result, ledger_svc.Reservation{}
 	}
-	var canApplyDiscount bool = offer_utils.IsOfferApplicable(offer.Conditions, offer.FactsToValidate(), fact)
+	reservation, err := o.ledger.Reserve(offer.Code, customer, limitsOf(offer))
+	if err != nil {
+		return limitReached(offer, result.Code, err), reservation
+	}
+	return result, reservation
+}
+
+func limitsOf(offer models.Offer) ledger_svc.Limits {
+	return ledger_svc.Limits{Total: offer.MaxRedemptions, PerCustomer: offer.MaxPerCustomer}
+}
+
+// Result of an applicable offer whose redemption limit is reached
+func limitReached(offer models.Offer, code models.OfferCode, err error) models.DiscountResult {
+	reason := fmt.Sprintf("limited to %d redemptions", offer.MaxRedemptions)
+	switch {
+	case errors.Is(err, error_utils.ErrCustomerRequired):
+		reason = fmt.Sprintf("limited to %d redemptions per customer, customer is required", offer.MaxPerCustomer)
+	case errors.Is(err, error_utils.ErrCustomerLimit):
+		reason = fmt.Sprintf("limited to %d redemptions per customer", offer.MaxPerCustomer)
+	}
+	return models.DiscountResult{Code: code, Status: models.DiscountLimitReached, Reason: reason}
+}
+
+func (o *offerService) CommitRedemptions() error {
+	return o.ledger.Commit()
+}
+
+func (o *offerService) ReleaseRedemptions() {
+	o.ledger.Release()
+}
+
+// Result of a code without offer, no code (NA) is not applicable whereas any other code is unknown
+func missingOffer(index *offerIndex, code models.OfferCode) models.DiscountResult {
+	if code == "" || code == models.NoOfferCode {
+		return models.DiscountResult{Code: code, Status: models.DiscountNotApplicable, Reason: "no offer code"}
+	}
+	result := models.DiscountResult{Code: code, Status: models.DiscountUnknownCode, Reason: "no offer found for the code"}
+	result.Suggestion, _ = offer_utils.SuggestCode(code, index.codes)
+	return result
+}
+
+// Unknown codes are errors in strict mode
+func (o *offerService) checkUnknown(index *offerIndex, codes []models.OfferCode) error {
+	if !o.strict {
+		return nil
+	}
+	for _, code := range codes {
+		if _, ok := index.byCode[code]; ok {
+			continue
+		}
+		if missing := missingOffer(index, code); missing.Status == models.DiscountUnknownCode {
+			return error_utils.ErrOfferCodeUnknown(code, missing.Suggestion)
+		}
+	}
+	return nil
+}
 
-	if canApplyDiscount {
-		return deliveryCost * offer.Discount, nil
+func hasKnownCode(index *offerIndex, codes []models.OfferCode) bool {
+	for _, code := range codes {
+		if _, ok := index.byCode[code]; ok {
+			return true
+		}
 	}
-	return 0, nil
+	return false
 }
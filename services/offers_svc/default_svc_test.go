@@ -1,10 +1,24 @@
 package offers_svc
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/services/ledger_svc"
+	"github.com/lakshmaji/delivery-shell/services/store_svc"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
 )
 
 func TestApplicableDiscount(t *testing.T) {
@@ -31,10 +45,10 @@ func TestApplicableDiscount(t *testing.T) {
 		return offersSlice, nil
 
 	}
-	svc := NewOffersService(mockIoReadFile)
+	svc := NewOffersService(mockIoReadFile, "offers.json", models.RoundHalfUp)
 
 	type args struct {
-		deliveryCost float64
+		deliveryCost models.Money
 		code         models.OfferCode
 		weight       models.Weight
 		distance     models.Distance
@@ -42,47 +56,57 @@ func TestApplicableDiscount(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want float64
+		want models.Money
 	}{
 		{
 			name: "TestApplicableDiscount",
 			args: args{
-				deliveryCost: float64(100),
+				deliveryCost: models.Money(10000),
 				code:         models.OfferCode("A"),
 				weight:       models.Weight(10),
 				distance:     models.Distance(5),
 			},
-			want: float64(20),
+			want: models.Money(2000),
+		},
+		{
+			name: "TestApplicableDiscount (rounds to the nearest minor unit)",
+			args: args{
+				deliveryCost: models.Money(123),
+				code:         models.OfferCode("A"),
+				weight:       models.Weight(10),
+				distance:     models.Distance(5),
+			},
+			want: models.Money(25),
 		},
 		{
 			name: "TestApplicableDiscount",
 			args: args{
-				deliveryCost: float64(100),
+				deliveryCost: models.Money(10000),
 				code:         models.OfferCode("INVALID"),
 				weight:       models.Weight(10),
 				distance:     models.Distance(5),
 			},
-			want: float64(0),
+			want: models.Money(0),
 		},
 		{
 			name: "TestApplicableDiscount",
 			args: args{
-				deliveryCost: float64(100),
+				deliveryCost: models.Money(10000),
 				weight:       models.Weight(10),
 				distance:     models.Distance(5),
 			},
-			want: float64(0),
+			want: models.Money(0),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := svc.ApplicableDiscount(tt.args.deliveryCost, tt.args.code, tt.args.weight, tt.args.distance)
+			got, err := svc.ApplicableDiscount(tt.args.deliveryCost, tt.args.code, models.Fact{Weight: tt.args.weight, Distance: tt.args.distance})
 			if err != nil {
 				t.Error("should not throw error")
 			}
-			if got != tt.want {
-				t.Errorf("ApplicableDiscount() = %v, want %v", got, tt.want)
+			if got.Amount != tt.want {
+				t.Errorf("ApplicableDiscount() = %v, want %v", got.Amount, tt.want)
 			}
 		})
 	}
@@ -95,10 +119,10 @@ func TestApplicableDiscountFail(t *testing.T) {
 
 	}
 
-	svc := NewOffersService(mockIoReadFile)
+	svc := NewOffersService(mockIoReadFile, "offers.json", models.RoundHalfUp)
 
 	type args struct {
-		deliveryCost float64
+		deliveryCost models.Money
 		code         models.OfferCode
 		weight       models.Weight
 		distance     models.Distance
@@ -106,29 +130,581 @@ func TestApplicableDiscountFail(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want float64
+		want models.Money
 	}{
 		{
 			name: "TestApplicableDiscount",
 			args: args{
-				deliveryCost: float64(100),
+				deliveryCost: models.Money(10000),
 				code:         models.OfferCode("A"),
 				weight:       models.Weight(10),
 				distance:     models.Distance(5),
 			},
-			want: float64(0),
+			want: models.Money(0),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			totalCost, err := svc.ApplicableDiscount(tt.args.deliveryCost, tt.args.code, tt.args.weight, tt.args.distance)
+			totalCost, err := svc.ApplicableDiscount(tt.args.deliveryCost, tt.args.code, models.Fact{Weight: tt.args.weight, Distance: tt.args.distance})
 			if err == nil {
 				t.Error("Should throw error")
 			}
-			if totalCost != tt.want {
-				t.Errorf("ApplicableDiscount() = %f, want %f", totalCost, tt.want)
+			if totalCost.Amount != tt.want {
+				t.Errorf("ApplicableDiscount() = %v, want %v", totalCost.Amount, tt.want)
 			}
 		})
 	}
 }
+
+func TestOffersAreLoadedOnce(t *testing.T) {
+	var calls int
+	var filenames []string
+	mockIoReadFile := func(filename string) ([]models.Offer, error) {
+		calls++
+		filenames = append(filenames, filename)
+		return []models.Offer{
+			{
+				Code:       "A",
+				Discount:   0.1,
+				Conditions: []models.Condition{{Fact: "weight", Operator: "lessThan", Value: 20}},
+			},
+		}, nil
+	}
+
+	svc := NewOffersService(mockIoReadFile, "./testdata/summer.json", models.RoundHalfUp)
+	for i := 0; i < 5; i++ {
+		got, err := svc.ApplicableDiscount(models.Money(10000), "A", models.Fact{Weight: 10, Distance: 5})
+		if err != nil {
+			t.Error("should not throw error")
+		}
+		if got.Amount != models.Money(1000) {
+			t.Errorf("ApplicableDiscount() = %v, want %v", got.Amount, models.Money(1000))
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("offers should be loaded once, loaded %d times", calls)
+	}
+	if filenames[0] != "./testdata/summer.json" {
+		t.Errorf("offers should be loaded from configured file, loaded from %s", filenames[0])
+	}
+}
+
+// Packages of a batch run are priced with the same offers
+var benchFact = models.Fact{Weight: 10, Distance: 100}
+
+func BenchmarkApplicableDiscount(b *testing.B) {
+	svc := NewOffersService(offer_utils.LoadOffers, "../../offers.json", models.RoundHalfUp)
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.ApplicableDiscount(models.Money(70000), "OFR003", benchFact); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Offers file read and decoded for every package priced (behaviour before offers were cached)
+func BenchmarkApplicableDiscountWithoutCache(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		svc := NewOffersService(offer_utils.LoadOffers, "../../offers.json", models.RoundHalfUp)
+		if _, err := svc.ApplicableDiscount(models.Money(70000), "OFR003", benchFact); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Collects messages written by the watcher (go routine)
+type chanWriter struct {
+	messages chan string
+}
+
+func (w *chanWriter) Write(content interface{}) {
+	w.messages <- fmt.Sprint(content)
+}
+
+func (w *chanWriter) WriteError(content interface{}) {
+	w.messages <- fmt.Sprint(content)
+}
+
+func offersJSON(discount float64, operator string) string {
+	return fmt.Sprintf(`[{"code": "A", "discount": %v, "conditions": [{"fact": "weight", "operator": %q, "value": 20}]}]`, discount, operator)
+}
+
+func writeOffers(t testing.TB, filename string, content string, modTime time.Time) {
+	t.Helper()
+	// written aside and renamed, the watcher should never see a partially written file
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// file systems with coarse modification time would not notice quick successive writes
+	if err := os.Chtimes(tmp, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func discountFor(t testing.TB, svc OffersService) models.Money {
+	t.Helper()
+	discount, err := svc.ApplicableDiscount(models.Money(10000), "A", models.Fact{Weight: 10, Distance: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return discount.Amount
+}
+
+func TestReload(t *testing.T) {
+	var discount float64 = 0.1
+	var loadErr error
+	mockIoReadFile := func(filename string) ([]models.Offer, error) {
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		return []models.Offer{
+			{Code: "A", Discount: discount, Conditions: []models.Condition{{Fact: "weight", Operator: "lessThan", Value: 20}}},
+		}, nil
+	}
+	svc := NewOffersService(mockIoReadFile, "offers.json", models.RoundHalfUp)
+
+	if got := discountFor(t, svc); got != 1000 {
+		t.Errorf("ApplicableDiscount() = %v, want %v", got, models.Money(1000))
+	}
+
+	discount = 0.2
+	if err := svc.Reload(); err != nil {
+		t.Errorf("should not throw error, received %v", err)
+	}
+	if got := discountFor(t, svc); got != 2000 {
+		t.Errorf("ApplicableDiscount() = %v, want %v (reloaded offers)", got, models.Money(2000))
+	}
+
+	loadErr = errors.New("unable to read contents")
+	if err := svc.Reload(); err == nil {
+		t.Error("Should throw error")
+	}
+	if got := discountFor(t, svc); got != 2000 {
+		t.Errorf("ApplicableDiscount() = %v, want %v (last valid offers)", got, models.Money(2000))
+	}
+}
+
+func TestWatch(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "offers.json")
+	modTime := time.Now().Add(-time.Hour)
+	writeOffers(t, filename, offersJSON(0.1, models.LessThan), modTime)
+
+	svc := NewOffersService(offer_utils.LoadOffers, filename, models.RoundHalfUp)
+	if got := discountFor(t, svc); got != 1000 {
+		t.Errorf("ApplicableDiscount() = %v, want %v", got, models.Money(1000))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	writer := &chanWriter{messages: make(chan string, 10)}
+	svc.Watch(ctx, 5*time.Millisecond, writer)
+	waitFor := func(expected string) {
+		t.Helper()
+		select {
+		case message := <-writer.messages:
+			if !strings.HasPrefix(message, expected) {
+				t.Fatalf("expected %q, received %q", expected, message)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected %q, received nothing", expected)
+		}
+	}
+	writeOffers(t, filename, offersJSON(0.2, models.LessThan), modTime.Add(time.Minute))
+	waitFor(fmt.Sprintf("Offers reloaded from %s (1 offers)", filename))
+	if got := discountFor(t, svc); got != 2000 {
+		t.Errorf("ApplicableDiscount() = %v, want %v (reloaded offers)", got, models.Money(2000))
+	}
+
+	writeOffers(t, filename, offersJSON(0.3, "above"), modTime.Add(2*time.Minute))
+	waitFor(fmt.Sprintf("Offers reload from %s failed, last valid offers are active", filename))
+	if got := discountFor(t, svc); got != 2000 {
+		t.Errorf("ApplicableDiscount() = %v, want %v (last valid offers)", got, models.Money(2000))
+	}
+}
+
+func TestReloadWhilePricing(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "offers.json")
+	writeOffers(t, filename, offersJSON(0.1, models.LessThan), time.Now())
+	svc := NewOffersService(offer_utils.LoadOffers, filename, models.RoundHalfUp)
+
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 50; i++ {
+			if err := svc.Reload(); err != nil {
+				t.Error(err)
+			}
+		}
+		done <- true
+	}()
+	for i := 0; i < 200; i++ {
+		if got := discountFor(t, svc); got != 1000 {
+			t.Errorf("ApplicableDiscount() = %v, want %v", got, models.Money(1000))
+		}
+	}
+	<-done
+}
+
+func TestApplicableDiscountValidity(t *testing.T) {
+	validFrom := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockIoReadFile := func(filename string) ([]models.Offer, error) {
+		return []models.Offer{
+			{
+				Code:       "XMAS",
+				Discount:   0.1,
+				Conditions: []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 20}},
+				ValidFrom:  &validFrom,
+				ValidUntil: &validUntil,
+				Schedule:   &models.OfferSchedule{Days: []string{"saturday", "sunday"}},
+			},
+		}, nil
+	}
+
+	tt := []struct {
+		name     string
+		now      time.Time
+		code     models.OfferCode
+		weight   models.Weight
+		expected models.DiscountResult
+	}{
+		{
+			name:     "active",
+			now:      time.Date(2026, 12, 5, 10, 0, 0, 0, time.UTC),
+			code:     "XMAS",
+			weight:   10,
+			expected: models.DiscountResult{Code: "XMAS", Amount: 1000, Status: models.DiscountApplied},
+		},
+		{
+			name:     "not yet active",
+			now:      time.Date(2026, 11, 28, 10, 0, 0, 0, time.UTC),
+			code:     "XMAS",
+			weight:   10,
+			expected: models.DiscountResult{Code: "XMAS", Status: models.DiscountNotActive, Reason: "active from 2026-12-01T00:00:00Z"},
+		},
+		{
+			name:     "expired",
+			now:      validUntil,
+			code:     "XMAS",
+			weight:   10,
+			expected: models.DiscountResult{Code: "XMAS", Status: models.DiscountExpired, Reason: "expired at 2027-01-01T00:00:00Z"},
+		},
+		{
+			name:     "outside schedule",
+			now:      time.Date(2026, 12, 7, 10, 0, 0, 0, time.UTC),
+			code:     "XMAS",
+			weight:   10,
+			expected: models.DiscountResult{Code: "XMAS", Status: models.DiscountNotActive, Reason: "active only on saturday, sunday"},
+		},
+		{
+			name:     "conditions are not satisfied",
+			now:      time.Date(2026, 12, 5, 10, 0, 0, 0, time.UTC),
+			code:     "XMAS",
+			weight:   50,
+			expected: models.DiscountResult{Code: "XMAS", Status: models.DiscountNotApplicable, Reason: "conditions are not satisfied"},
+		},
+		{
+			name:     "without offer",
+			now:      time.Date(2026, 12, 5, 10, 0, 0, 0, time.UTC),
+			code:     "NA",
+			weight:   10,
+			expected: models.DiscountResult{Code: "NA", Status: models.DiscountNotApplicable, Reason: "no offer code"},
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			clock := func() time.Time { return test.now }
+			svc := NewOffersServiceWithOptions(mockIoReadFile, "offers.json", models.RoundHalfUp, Options{Clock: clock})
+			got, err := svc.ApplicableDiscount(models.Money(10000), test.code, models.Fact{Weight: test.weight, Distance: 5})
+			if err != nil {
+				t.Errorf("should not throw error, received %v", err)
+			}
+			if got != test.expected {
+				t.Errorf("ApplicableDiscount() = %+v, want %+v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestApplicableDiscounts(t *testing.T) {
+	weightBelow20 := []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 20}}
+	mockIoReadFile := func(filename string) ([]models.Offer, error) {
+		return []models.Offer{
+			{Code: "A", Discount: 0.1, Conditions: weightBelow20, Stacking: models.StackingStackable},
+			{Code: "B", Discount: 0.05, Conditions: weightBelow20, Stacking: models.StackingStackable, StackMode: models.StackSequential},
+			{Code: "C", Discount: 0.5, Conditions: []models.Condition{{Fact: "weight", Operator: models.GreaterThan, Value: 100}}},
+		}, nil
+	}
+	svc := NewOffersService(mockIoReadFile, "offers.json", models.RoundHalfUp)
+
+	got, err := svc.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"A", "NA", "B", "C"}, models.Fact{Weight: 10, Distance: 5})
+	if err != nil {
+		t.Errorf("should not throw error, received %v", err)
+	}
+	expected := []models.DiscountResult{
+		{Code: "A", Amount: 1000, Status: models.DiscountApplied},
+		{Code: "NA", Status: models.DiscountNotApplicable, Reason: "no offer code"},
+		// 5% of the remaining 90
+		{Code: "B", Amount: 450, Status: models.DiscountApplied},
+		{Code: "C", Status: models.DiscountNotApplicable, Reason: "conditions are not satisfied"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ApplicableDiscounts() = %+v, want %+v", got, expected)
+	}
+
+	failing := NewOffersService(func(string) ([]models.Offer, error) { return nil, errors.New("unable to read contents") }, "offers.json", models.RoundHalfUp)
+	if _, err := failing.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"A"}, models.Fact{}); err == nil {
+		t.Error("Should throw error")
+	}
+}
+
+func TestApplicableDiscountsAutoApply(t *testing.T) {
+	optOut := false
+	mockIoReadFile := func(filename string) ([]models.Offer, error) {
+		return []models.Offer{
+			{Code: "A", Discount: 0.1, Conditions: []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 20}}},
+			{Code: "B", Discount: 0.2, Conditions: []models.Condition{{Fact: "distance", Operator: models.LessThan, Value: 10}}},
+			{Code: "C", Discount: 0.2, Conditions: []models.Condition{{Fact: "distance", Operator: models.LessThan, Value: 10}}},
+			{Code: "D", Discount: 0.5, Conditions: []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 20}}, AutoApply: &optOut},
+			{Code: "E", Discount: 0.9, Conditions: []models.Condition{{Fact: "weight", Operator: models.GreaterThan, Value: 100}}},
+		}, nil
+	}
+	svc := NewOffersServiceWithOptions(mockIoReadFile, "offers.json", models.RoundHalfUp, Options{AutoApply: true})
+	fact := models.Fact{Weight: 10, Distance: 5}
+
+	tests := []struct {
+		name     string
+		codes    []models.OfferCode
+		expected []models.DiscountResult
+	}{
+		{
+			name:     "no code",
+			codes:    []models.OfferCode{},
+			expected: []models.DiscountResult{{Code: "B", Amount: 2000, Status: models.DiscountApplied, AutoApplied: true}},
+		},
+		{
+			name:  "unknown code",
+			codes: []models.OfferCode{"NA"},
+			expected: []models.DiscountResult{
+				{Code: "NA", Status: models.DiscountNotApplicable, Reason: "no offer code"},
+				{Code: "B", Amount: 2000, Status: models.DiscountApplied, AutoApplied: true},
+			},
+		},
+		{
+			name:     "known code (not applicable) is kept",
+			codes:    []models.OfferCode{"E"},
+			expected: []models.DiscountResult{{Code: "E", Status: models.DiscountNotApplicable, Reason: "conditions are not satisfied"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.ApplicableDiscounts(models.Money(10000), tt.codes, fact)
+			if err != nil {
+				t.Errorf("should not throw error, received %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ApplicableDiscounts() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+
+	disabled := NewOffersService(mockIoReadFile, "offers.json", models.RoundHalfUp)
+	got, _ := disabled.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"NA"}, fact)
+	if models.TotalDiscount(got) != 0 {
+		t.Errorf("auto apply is opt-in, received %+v", got)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	mockIoReadFile := func(filename string) ([]models.Offer, error) {
+		return []models.Offer{
+			{Code: "A", Discount: 0.1, Conditions: []models.Condition{
+				{Fact: "weight", Operator: models.LessThan, Value: 20},
+				{Fact: "distance", Operator: models.GreaterThan, Value: 50},
+			}},
+			{Code: "B", Discount: 0.1, Conditions: []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 20}}},
+			{Code: "C", Discount: 0.1, Conditions: []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 20}}, ValidUntil: &time.Time{}},
+		}, nil
+	}
+	svc := NewOffersService(mockIoReadFile, "offers.json", models.RoundHalfUp)
+
+	got, err := svc.Explain([]models.OfferCode{"A", "B", "C", "NA"}, models.Fact{Weight: 10, Distance: 5}, nil)
+	if err != nil {
+		t.Errorf("should not throw error, received %v", err)
+	}
+	weightBelow20 := models.ConditionResult{Fact: "weight", Operator: "lessThan", Expected: "20", Actual: "10", Pass: true}
+	expected := []models.OfferExplanation{
+		{Code: "A", Found: true, Status: models.DiscountNotApplicable, Reason: "conditions are not satisfied", Conditions: []models.ConditionResult{
+			weightBelow20,
+			{Fact: "distance", Operator: "greaterThan", Expected: "50", Actual: "5", Pass: false},
+		}},
+		{Code: "B", Found: true, Status: models.DiscountApplied, Conditions: []models.ConditionResult{weightBelow20}},
+		{Code: "C", Found: true, Status: models.DiscountExpired, Reason: "expired at 0001-01-01T00:00:00Z", Conditions: []models.ConditionResult{weightBelow20}},
+		{Code: "NA", Status: models.DiscountNotApplicable, Reason: "no offer code"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Explain() = %+v, want %+v", got, expected)
+	}
+
+	failing := NewOffersService(func(string) ([]models.Offer, error) { return nil, errors.New("unable to read contents") }, "offers.json", models.RoundHalfUp)
+	if _, err := failing.Explain([]models.OfferCode{"A"}, models.Fact{}, nil); err == nil {
+		t.Error("Should throw error")
+	}
+}
+
+func TestUnknownOfferCodes(t *testing.T) {
+	mockIoReadFile := func(filename string) ([]models.Offer, error) {
+		return []models.Offer{
+			{Code: "OFR001", Discount: 0.1, Conditions: []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 20}}},
+			{Code: "OFR002", Discount: 0.1, Conditions: []models.Condition{{Fact: "weight", Operator: models.GreaterThan, Value: 20}}},
+		}, nil
+	}
+	fact := models.Fact{Weight: 10, Distance: 5}
+	codes := []models.OfferCode{"OFR002", "OFR0O1", "SUMMER", "NA"}
+	expected := []models.DiscountResult{
+		{Code: "OFR002", Status: models.DiscountNotApplicable, Reason: "conditions are not satisfied"},
+		{Code: "OFR0O1", Status: models.DiscountUnknownCode, Reason: "no offer found for the code", Suggestion: "OFR001"},
+		{Code: "SUMMER", Status: models.DiscountUnknownCode, Reason: "no offer found for the code"},
+		{Code: "NA", Status: models.DiscountNotApplicable, Reason: "no offer code"},
+	}
+
+	svc := NewOffersService(mockIoReadFile, "offers.json", models.RoundHalfUp)
+	got, err := svc.ApplicableDiscounts(models.Money(10000), codes, fact)
+	if err != nil {
+		t.Errorf("should not throw error, received %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ApplicableDiscounts() = %+v, want %+v", got, expected)
+	}
+
+	strict := NewOffersServiceWithOptions(mockIoReadFile, "offers.json", models.RoundHalfUp, Options{Strict: true})
+	if _, err := strict.ApplicableDiscounts(models.Money(10000), codes, fact); err == nil || err.Error() != "Unknown offer code OFR0O1, did you mean OFR001?" {
+		t.Errorf("ApplicableDiscounts() error = %v, want unknown offer code", err)
+	}
+	if _, err := strict.ApplicableDiscount(models.Money(10000), "SUMMER", fact); !errors.Is(err, error_utils.ErrUnknownOfferCode) {
+		t.Errorf("ApplicableDiscount() error = %v, want %v", err, error_utils.ErrUnknownOfferCode)
+	}
+	if _, err := strict.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"OFR002", "NA"}, fact); err != nil {
+		t.Errorf("known codes and no code should not throw error, received %v", err)
+	}
+}
+
+func TestRedemptionLimits(t *testing.T) {
+	weightBelow20 := []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 20}}
+	mockIoReadFile := func(filename string) ([]models.Offer, error) {
+		return []models.Offer{
+			{Code: "FIRST2", Discount: 0.1, Conditions: weightBelow20, MaxRedemptions: 2},
+			{Code: "ONCE", Discount: 0.1, Conditions: weightBelow20, MaxPerCustomer: 1},
+			{Code: "A", Discount: 0.05, Conditions: weightBelow20, Stacking: models.StackingStackable},
+			{Code: "B", Discount: 0.2, Conditions: weightBelow20},
+		}, nil
+	}
+	ledger := ledger_svc.NewMemoryLedger()
+	svc := NewOffersServiceWithOptions(mockIoReadFile, "offers.json", models.RoundHalfUp, Options{Ledger: ledger})
+	fact := models.Fact{Weight: 10, Distance: 5, Customer: "C1"}
+
+	statuses := []models.DiscountStatus{}
+	for i := 0; i < 3; i++ {
+		result, err := svc.ApplicableDiscount(models.Money(10000), "FIRST2", fact)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses = append(statuses, result.Status)
+	}
+	if !reflect.DeepEqual(statuses, []models.DiscountStatus{models.DiscountApplied, models.DiscountApplied, models.DiscountLimitReached}) {
+		t.Errorf("ApplicableDiscount() statuses = %v, want the third one limited", statuses)
+	}
+
+	got, _ := svc.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"ONCE"}, fact)
+	if !got[0].IsApplied() {
+		t.Errorf("first redemption of the customer should be applied, received %+v", got[0])
+	}
+	got, _ = svc.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"ONCE"}, fact)
+	expected := models.DiscountResult{Code: "ONCE", Status: models.DiscountLimitReached, Reason: "limited to 1 redemptions per customer"}
+	if got[0] != expected {
+		t.Errorf("ApplicableDiscounts() = %+v, want %+v", got[0], expected)
+	}
+	got, _ = svc.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"ONCE"}, models.Fact{Weight: 10, Distance: 5})
+	if expected := "limited to 1 redemptions per customer, customer is required"; got[0].Reason != expected {
+		t.Errorf("ApplicableDiscounts() reason = %q, want %q", got[0].Reason, expected)
+	}
+
+	// B is applied instead of A, A is not redeemed
+	if _, err := svc.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"A", "B"}, fact); err != nil {
+		t.Fatal(err)
+	}
+	if total, _ := ledger.Redemptions("A", ""); total != 0 {
+		t.Errorf("not combined discount should not be redeemed, received %d redemptions", total)
+	}
+
+	// failed run
+	svc.ReleaseRedemptions()
+	if total, _ := ledger.Redemptions("FIRST2", ""); total != 0 {
+		t.Errorf("released redemptions = %d, want 0", total)
+	}
+	if _, err := svc.ApplicableDiscount(models.Money(10000), "FIRST2", fact); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CommitRedemptions(); err != nil {
+		t.Fatal(err)
+	}
+	if total, _ := ledger.Redemptions("FIRST2", ""); total != 1 {
+		t.Errorf("committed redemptions = %d, want 1", total)
+	}
+
+	// the granted discount is explained as applied, although it is the last redemption of the offer
+	granted, _ := svc.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"FIRST2"}, fact)
+	explanations, _ := svc.Explain([]models.OfferCode{"FIRST2"}, fact, granted)
+	if explanations[0].Status != models.DiscountApplied {
+		t.Errorf("Explain() = %+v, want applicable", explanations[0])
+	}
+	limited, _ := svc.ApplicableDiscounts(models.Money(10000), []models.OfferCode{"FIRST2"}, fact)
+	explanations, _ = svc.Explain([]models.OfferCode{"FIRST2"}, fact, limited)
+	if explanations[0].Status != models.DiscountLimitReached || explanations[0].Reason != "limited to 2 redemptions" {
+		t.Errorf("Explain() = %+v, want limit reached", explanations[0])
+	}
+	if total, _ := ledger.Redemptions("FIRST2", ""); total != 2 {
+		t.Errorf("explain should not redeem, received %d redemptions", total)
+	}
+}
+
+func TestOffersServiceWithStore(t *testing.T) {
+	var mu sync.Mutex
+	content, etag := offersJSON(0.1, models.LessThan), `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	svc := NewOffersServiceWithStore(store_svc.NewHTTPStore(server.URL, server.Client()), models.RoundHalfUp, Options{})
+	if got := discountFor(t, svc); got != 1000 {
+		t.Errorf("ApplicableDiscount() = %v, want %v", got, models.Money(1000))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	writer := &chanWriter{messages: make(chan string, 10)}
+	svc.Watch(ctx, 5*time.Millisecond, writer)
+
+	mu.Lock()
+	content, etag = offersJSON(0.2, models.LessThan), `"v2"`
+	mu.Unlock()
+	select {
+	case message := <-writer.messages:
+		if expected := fmt.Sprintf("Offers reloaded from %s (1 offers)", server.URL); message != expected {
+			t.Fatalf("expected %q, received %q", expected, message)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected offers to be reloaded")
+	}
+	if got := discountFor(t, svc); got != 2000 {
+		t.Errorf("ApplicableDiscount() = %v, want %v (reloaded offers)", got, models.Money(2000))
+	}
+}
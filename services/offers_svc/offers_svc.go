@@ -1,11 +1,54 @@
 package offers_svc
 
 import (
+	"context"
+	"time"
+
+	"github.com/lakshmaji/delivery-shell/clients"
 	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/services/ledger_svc"
 )
 
 type OffersService interface {
 	// Validate whether discount is applicable or not
-	// returns computed discount (applicable)
-	ApplicableDiscount(deliveryCost float64, code models.OfferCode, wt models.Weight, dt models.Distance) (float64, error)
+	// Offer should be active (validity period, schedule) at the time of evaluation,
+	// and its conditions are evaluated against the given facts (of the package)
+	// returns computed discount (applicable) rounded to the nearest minor unit, otherwise the reason
+	// unknown codes carry the closest known code (did you mean), and are errors in strict mode
+	// a redemption is reserved for the granted discount, offers whose redemption limit is reached are not applied
+	ApplicableDiscount(deliveryCost models.Money, code models.OfferCode, fact models.Fact) (models.DiscountResult, error)
+	// Applicable discounts of several offer codes (of a package), combined as per the stacking rules of the offers
+	// returns a result for every code (in the given order), followed by the auto applied offer (if enabled and no code is known)
+	ApplicableDiscounts(deliveryCost models.Money, codes []models.OfferCode, fact models.Fact) ([]models.DiscountResult, error)
+	// Why every offer code (of a package) did or did not apply, along with the outcome of every condition
+	// unknown codes are reported as not found, combination with other offers is not considered
+	// redemption limits are taken from the results of the codes (as granted by ApplicableDiscounts), nothing is reserved
+	Explain(codes []models.OfferCode, fact models.Fact, results []models.DiscountResult) ([]models.OfferExplanation, error)
+	// Redemptions reserved by the granted discounts become permanent, at the end of a run
+	CommitRedemptions() error
+	// Redemptions reserved by the granted discounts are dropped, when a run fails partway
+	ReleaseRedemptions()
+}
+
+// Current time, offers validity period and schedule are evaluated against it
+type Clock func() time.Time
+
+type Options struct {
+	Clock Clock // time.Now when not specified
+	// Packages without a known offer code get the active offer with the highest discount (unless the offer opts out)
+	AutoApply bool
+	// Unknown offer codes are errors (ErrUnknownOfferCode), otherwise they are reported as unknownCode results
+	Strict bool
+	// Redemptions of granted discounts, to enforce offer usage limits (in memory when not specified)
+	Ledger ledger_svc.RedemptionLedger
+}
+
+// Offers service whose offers can be replaced without restarting the process
+type ReloadableOffersService interface {
+	OffersService
+	// Loads offers again, the active offers are kept when the new offers are invalid
+	Reload() error
+	// Reloads offers whenever the offers source changes (polled in the background), until the context is done
+	// returns once the current revision of the source is known, changes made afterwards are reloaded
+	Watch(ctx context.Context, interval time.Duration, writer clients.BaseWriter)
 }
@@ -9,24 +9,30 @@ import (
 	"github.com/lakshmaji/delivery-shell/models"
 	"github.com/lakshmaji/delivery-shell/utils/common_utils"
 	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+	"github.com/lakshmaji/delivery-shell/utils/fleet_utils"
 	"github.com/lakshmaji/delivery-shell/utils/msg_utils"
 )
 
 type packageInputSvc struct {
-	reader io.Reader
+	// shared by every scan, so that input buffered by one scan is not lost to the next (ex: piped input, batch files)
+	reader *bufio.Reader
 }
 
 // Handles responsibility of capturing inputs from **stdin**
 func NewShellReader(reader io.Reader) PackageInputService {
-	return &packageInputSvc{reader}
+	return &packageInputSvc{bufio.NewReader(reader)}
+}
+
+// Next line of the input, without the line ending (empty when input is exhausted)
+func (d *packageInputSvc) readLine() string {
+	line, _ := d.reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n")
 }
 
 // Reads base delivery cost and no of packages
 func (d *packageInputSvc) ScanBaseDeliveryCostPkgCount(writer clients.BaseWriter) (models.BaseDeliveryCost, int, error) {
-	scanner := bufio.NewScanner(d.reader)
 	writer.Write(msg_utils.MsgBaseCostPkgCountHeader)
-	scanner.Scan()
-	text := scanner.Text()
+	text := d.readLine()
 	if len(text) == 0 {
 		return 0, 0, error_utils.ErrMissingInput
 	}
@@ -51,18 +57,17 @@ func (d *packageInputSvc) ScanBaseDeliveryCostPkgCount(writer clients.BaseWriter
 
 // Reads package details from user input
 func (d *packageInputSvc) ScanNPackageDetails(writer clients.BaseWriter, noOfPackages int) ([]*models.PackageDetails, error) {
-	scanner := bufio.NewScanner(d.reader)
 	var packages []*models.PackageDetails
 	for i := 0; i < noOfPackages; i++ {
 		writer.Write(msg_utils.MsgPackageDetailsHeader)
-		scanner.Scan()
-		text := scanner.Text()
+		text := d.readLine()
 		if len(text) == 0 {
 			return nil, error_utils.ErrMissingInput
 		}
 
+		// customer id is optional
 		input := strings.Fields(text)
-		if len(input) != 4 {
+		if len(input) != 4 && len(input) != 5 {
 			return nil, error_utils.ErrPackageDetailsFormat
 		}
 		weight, err := common_utils.ConvertStrToFloat64(input[1])
@@ -73,11 +78,19 @@ func (d *packageInputSvc) ScanNPackageDetails(writer clients.BaseWriter, noOfPac
 		if err != nil {
 			return nil, err
 		}
+		// several offer codes are comma separated, ex: OFR001,OFR003
+		codes := models.ParseOfferCodes(input[3])
 		box := models.PackageDetails{
 			Id:       models.PackageID(input[0]),
 			Weight:   weight,
 			Distance: distance,
-			Code:     models.OfferCode(input[3]),
+			Codes:    codes,
+		}
+		if len(codes) > 0 {
+			box.Code = codes[0]
+		}
+		if len(input) == 5 {
+			box.Customer = models.CustomerID(input[4])
 		}
 
 		packages = append(packages, &box)
@@ -87,35 +100,86 @@ func (d *packageInputSvc) ScanNPackageDetails(writer clients.BaseWriter, noOfPac
 }
 
 // no_of_vehicles <space> max_speed_of_all_vehicles_in_km_per_hour <space> max_capacity_of_all_vehicles_in_kg
-// Reads base delivery cost and no of packages
-func (d *packageInputSvc) ScanVehicleDetails(writer clients.BaseWriter) (int, int, int, error) {
-	scanner := bufio.NewScanner(d.reader)
+// or no_of_vehicles alone, followed by details of every vehicle (mixed fleet)
+// Reads vehicles of the fleet
+func (d *packageInputSvc) ScanVehicleDetails(writer clients.BaseWriter) (models.Fleet, error) {
 	writer.Write(msg_utils.MsgVehiclesHeader)
-	scanner.Scan()
-	text := scanner.Text()
+	text := d.readLine()
 	if len(text) == 0 {
-		return 0, 0, 0, error_utils.ErrMissingInput
+		return nil, error_utils.ErrMissingInput
 	}
 
 	input := strings.Fields(text)
-	if len(input) != 3 {
-		return 0, 0, 0, error_utils.ErrVehicleDetailsFormat
+	if len(input) != 1 && len(input) != 3 {
+		return nil, error_utils.ErrVehicleDetailsFormat
 	}
 
 	noOfVehicles, err := common_utils.ConvertStrToInt(input[0])
 	if err != nil {
-		return 0, 0, 0, err
+		return nil, err
+	}
+	if len(input) == 1 {
+		return d.scanFleet(writer, noOfVehicles)
 	}
 	speed, err := common_utils.ConvertStrToInt(input[1])
 	if err != nil {
-		return 0, 0, 0, err
+		return nil, err
 	}
-	maxWeight, err := common_utils.ConvertStrToInt(input[2])
+	// capacity can be fractional, ex: 49.5
+	maxWeight, err := common_utils.ConvertStrToFloat64(input[2])
 	if err != nil {
-		return 0, 0, 0, err
+		return nil, err
+	}
+
+	fleet := models.NewFleet(noOfVehicles, speed, maxWeight)
+	if err := fleet_utils.ValidateFleet(fleet); err != nil {
+		return nil, err
+	}
+	return fleet, nil
+}
+
+// vehicle_id <space> type <space> capacity_in_kg <space> speed_in_km_per_hour <space> available_at_in_hrs (optional)
+// Reads details of every vehicle
+func (d *packageInputSvc) scanFleet(writer clients.BaseWriter, noOfVehicles int) (models.Fleet, error) {
+	var fleet models.Fleet
+	for i := 0; i < noOfVehicles; i++ {
+		writer.Write(msg_utils.MsgVehicleDetailsHeader)
+		text := d.readLine()
+		if len(text) == 0 {
+			return nil, error_utils.ErrMissingInput
+		}
+
+		input := strings.Fields(text)
+		if len(input) != 4 && len(input) != 5 {
+			return nil, error_utils.ErrVehicleFormat
+		}
+		capacity, err := common_utils.ConvertStrToFloat64(input[2])
+		if err != nil {
+			return nil, err
+		}
+		speed, err := common_utils.ConvertStrToFloat64(input[3])
+		if err != nil {
+			return nil, err
+		}
+		vehicle := models.Vehicle{
+			Id:       models.VehicleID(input[0]),
+			Type:     input[1],
+			Capacity: capacity,
+			Speed:    speed,
+		}
+		if len(input) == 5 {
+			vehicle.AvailableAt, err = common_utils.ConvertStrToFloat64(input[4])
+			if err != nil {
+				return nil, err
+			}
+		}
+		fleet = append(fleet, vehicle)
 	}
 
-	return noOfVehicles, speed, maxWeight, nil
+	if err := fleet_utils.ValidateFleet(fleet); err != nil {
+		return nil, err
+	}
+	return fleet, nil
 }
 
 // Which version of program to run
@@ -124,9 +188,7 @@ func (d *packageInputSvc) ScanVehicleDetails(writer clients.BaseWriter) (int, in
 func (d *packageInputSvc) ScanProgramChoice(writer clients.BaseWriter) (string, error) {
 	writer.Write(msg_utils.MsgProgramChoice)
 
-	scanner := bufio.NewScanner(d.reader)
-	scanner.Scan()
-	timeComputeDecisionInput := scanner.Text()
+	timeComputeDecisionInput := d.readLine()
 
 	if len(timeComputeDecisionInput) == 0 {
 		return "", error_utils.ErrMissingInput
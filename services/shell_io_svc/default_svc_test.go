@@ -5,10 +5,13 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/lakshmaji/delivery-shell/clients"
+	"github.com/lakshmaji/delivery-shell/models"
 	"github.com/lakshmaji/delivery-shell/utils/error_utils"
 )
 
@@ -35,7 +38,12 @@ func seek(t testing.TB, reader *os.File) {
 	}
 }
 
+// Replaces the input, the reader is shared by every scan so leftovers of a previous input would be read next
 func writeToPrompt(t testing.TB, reader *os.File, input string) {
+	if err := reader.Truncate(0); err != nil {
+		t.Fatal(err)
+	}
+	seek(t, reader)
 	_, err := io.WriteString(reader, input)
 	if err != nil {
 		t.Fatal(err)
@@ -128,20 +136,52 @@ func TestScanVehicleDetails(t *testing.T) {
 
 	writeToPrompt(t, reader, "2 70 200\n")
 
-	vehiclesCount, speed, maxWeight, err := svc.ScanVehicleDetails(writer)
+	fleet, err := svc.ScanVehicleDetails(writer)
 	if err != nil {
 		t.Error("should not return error")
 	}
-	if vehiclesCount != 2 {
-		t.Errorf("Expected 2 vehicles, got %d", vehiclesCount)
+	if len(fleet) != 2 {
+		t.Errorf("Expected 2 vehicles, got %d", len(fleet))
 	}
-	if speed != 70 {
-		t.Errorf("Expected speed 70, got %d", speed)
+	if !reflect.DeepEqual(fleet, models.NewFleet(2, 70, 200)) {
+		t.Errorf("Expected 2 vehicles of speed 70 and weight capacity 200, got %+v", fleet)
+	}
+
+}
+
+func TestScanVehicleDetailsFractionalCapacity(t *testing.T) {
+	reader, writer, svc := mockIO(t)
+	defer reader.Close()
+
+	writeToPrompt(t, reader, "10 70 20.8\n")
+
+	fleet, err := svc.ScanVehicleDetails(writer)
+	if err != nil {
+		t.Error("should not return error")
 	}
-	if maxWeight != 200 {
-		t.Errorf("Expected weight capacity 200, got %d", maxWeight)
+	if fleet.MaxCapacity() != 20.8 {
+		t.Errorf("Expected weight capacity 20.8, got %v", fleet.MaxCapacity())
 	}
+}
+
+func TestScanVehicleDetailsFleet(t *testing.T) {
+	reader, writer, svc := mockIO(t)
+	defer reader.Close()
+
+	writeToPrompt(t, reader, "3\nBIKE1 bike 20 40\nVAN1 van 100 60\nTRUCK1 truck 300.5 50 1.5\n")
 
+	fleet, err := svc.ScanVehicleDetails(writer)
+	if err != nil {
+		t.Errorf("should not return error, received %v", err)
+	}
+	expected := models.Fleet{
+		{Id: "BIKE1", Type: "bike", Capacity: 20, Speed: 40},
+		{Id: "VAN1", Type: "van", Capacity: 100, Speed: 60},
+		{Id: "TRUCK1", Type: "truck", Capacity: 300.5, Speed: 50, AvailableAt: 1.5},
+	}
+	if !reflect.DeepEqual(fleet, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, fleet)
+	}
 }
 func TestScanVehicleDetailsErrors(t *testing.T) {
 	reader, writer, svc := mockIO(t)
@@ -158,10 +198,35 @@ func TestScanVehicleDetailsErrors(t *testing.T) {
 			Expected: error_utils.ErrMissingInput,
 		},
 		{
-			Name:     "provided vehicle count only",
+			Name:     "provided vehicle count without vehicles",
 			Input:    "3\n",
+			Expected: error_utils.ErrMissingInput,
+		},
+		{
+			Name:     "provided vehicle count and speed only",
+			Input:    "3 70\n",
 			Expected: error_utils.ErrVehicleDetailsFormat,
 		},
+		{
+			Name:     "provided vehicle without speed",
+			Input:    "1\nVAN1 van 100\n",
+			Expected: error_utils.ErrVehicleFormat,
+		},
+		{
+			Name:     "provided vehicle capacity as string",
+			Input:    "1\nVAN1 van hundred 60\n",
+			Expected: &strconv.NumError{Func: "ParseFloat", Num: "hundred", Err: strconv.ErrSyntax},
+		},
+		{
+			Name:     "provided vehicle availability as string",
+			Input:    "1\nVAN1 van 100 60 noon\n",
+			Expected: &strconv.NumError{Func: "ParseFloat", Num: "noon", Err: strconv.ErrSyntax},
+		},
+		{
+			Name:     "provided vehicles with same id",
+			Input:    "2\nVAN1 van 100 60\nVAN1 van 100 60\n",
+			Expected: error_utils.ErrFleetVehicle(1, `duplicate id "VAN1"`),
+		},
 		{
 			Name:     "provided more inputs than expected",
 			Input:    "3 70 10 60 70\n",
@@ -180,12 +245,37 @@ func TestScanVehicleDetailsErrors(t *testing.T) {
 		{
 			Name:     "provided vehicle weight capacity as string",
 			Input:    "10 70 twenty\n",
-			Expected: &strconv.NumError{Func: "Atoi", Num: "twenty", Err: strconv.ErrSyntax},
+			Expected: &strconv.NumError{Func: "ParseFloat", Num: "twenty", Err: strconv.ErrSyntax},
+		},
+		{
+			Name:     "provided no vehicles",
+			Input:    "0 70 200\n",
+			Expected: error_utils.ErrFleetEmpty,
+		},
+		{
+			Name:     "provided zero speed",
+			Input:    "1 0 200\n",
+			Expected: error_utils.ErrFleetVehicle(0, "speed should be positive"),
+		},
+		{
+			Name:     "provided negative weight capacity",
+			Input:    "1 70 -70\n",
+			Expected: error_utils.ErrFleetVehicle(0, "capacity should be positive"),
 		},
 		{
-			Name:     "provided vehicle weight capacity as decimal number",
-			Input:    "10 70 20.8\n",
-			Expected: &strconv.NumError{Func: "Atoi", Num: "20.8", Err: strconv.ErrSyntax},
+			Name:     "provided infinite weight capacity",
+			Input:    "1 70 Inf\n",
+			Expected: error_utils.ErrFleetVehicle(0, "capacity should be a finite number"),
+		},
+		{
+			Name:     "provided vehicle speed as NaN",
+			Input:    "1\nV1 van 200 NaN\n",
+			Expected: error_utils.ErrFleetVehicle(0, "speed should be a finite number"),
+		},
+		{
+			Name:     "provided infinite vehicle availability",
+			Input:    "1\nV1 van 200 60 +Inf\n",
+			Expected: error_utils.ErrFleetVehicle(0, "availableAt should be a finite number"),
 		},
 	}
 
@@ -196,13 +286,13 @@ func TestScanVehicleDetailsErrors(t *testing.T) {
 
 			writeToPrompt(t, reader, test.Input)
 
-			vehiclesCount, speed, maxWeight, err := svc.ScanVehicleDetails(writer)
+			fleet, err := svc.ScanVehicleDetails(writer)
 
 			if err == nil {
-				t.Error("should throw error")
+				t.Fatal("should throw error")
 			}
-			if vehiclesCount != 0 || speed != 0 || maxWeight != 0 {
-				t.Errorf("expected defaults %d, %d, %d", vehiclesCount, speed, maxWeight)
+			if fleet != nil {
+				t.Errorf("expected no vehicles, received %+v", fleet)
 			}
 
 			if err.Error() != test.Expected.Error() {
@@ -228,6 +318,63 @@ func TestScanNPackageDetails(t *testing.T) {
 	}
 
 }
+func TestScanNPackageDetailsOfferCodes(t *testing.T) {
+	reader, writer, svc := mockIO(t)
+	defer reader.Close()
+
+	writeToPrompt(t, reader, "PKG1 10 10 OFR001,OFR003\n")
+
+	boxes, err := svc.ScanNPackageDetails(writer, 1)
+	if err != nil {
+		t.Error("should not return error")
+	}
+	if len(boxes) != 1 {
+		t.Fatalf("Expected 1 box, got %d", len(boxes))
+	}
+	if boxes[0].Code != "OFR001" || !reflect.DeepEqual(boxes[0].Codes, []models.OfferCode{"OFR001", "OFR003"}) {
+		t.Errorf("Expected codes OFR001,OFR003, got %s %v", boxes[0].Code, boxes[0].Codes)
+	}
+}
+
+func TestScanNPackageDetailsCustomer(t *testing.T) {
+	reader, writer, svc := mockIO(t)
+	defer reader.Close()
+
+	writeToPrompt(t, reader, "PKG1 10 10 OFR001 CUST42\n")
+
+	boxes, err := svc.ScanNPackageDetails(writer, 1)
+	if err != nil {
+		t.Error("should not return error")
+	}
+	if len(boxes) != 1 || boxes[0].Customer != "CUST42" || boxes[0].Code != "OFR001" {
+		t.Errorf("Expected PKG1 of customer CUST42, got %+v", boxes)
+	}
+}
+
+// Every scan reads from the same (piped) input, lines read ahead by one scan are not lost
+func TestScanPipedInput(t *testing.T) {
+	var output bytes.Buffer
+	writer := clients.NewShellWriter(&output, true)
+	svc := NewShellReader(strings.NewReader("yes\n100 2\r\nPKG1 5 5 OFR001\nPKG2 15 5 OFR002\n2 70 200\n"))
+
+	choice, err := svc.ScanProgramChoice(writer)
+	if err != nil || choice != "yes" {
+		t.Fatalf("Expected choice yes, got %q %v", choice, err)
+	}
+	cost, count, err := svc.ScanBaseDeliveryCostPkgCount(writer)
+	if err != nil || cost != 100 || count != 2 {
+		t.Fatalf("Expected 100 2, got %v %d %v", cost, count, err)
+	}
+	boxes, err := svc.ScanNPackageDetails(writer, count)
+	if err != nil || len(boxes) != 2 || boxes[1].Id != "PKG2" {
+		t.Fatalf("Expected PKG1 and PKG2, got %+v %v", boxes, err)
+	}
+	fleet, err := svc.ScanVehicleDetails(writer)
+	if err != nil || !reflect.DeepEqual(fleet, models.NewFleet(2, 70, 200)) {
+		t.Fatalf("Expected 2 70 200, got %+v %v", fleet, err)
+	}
+}
+
 func TestScanNPackageDetailsErrors(t *testing.T) {
 	reader, writer, svc := mockIO(t)
 	defer reader.Close()
@@ -256,6 +403,12 @@ func TestScanNPackageDetailsErrors(t *testing.T) {
 			Expected:     error_utils.ErrPackageDetailsFormat,
 			noOfPackages: 3,
 		},
+		{
+			Name:         "Unexpected field after customer",
+			Input:        "PKG1 10 10 OFR002 CUST42 express",
+			Expected:     error_utils.ErrPackageDetailsFormat,
+			noOfPackages: 3,
+		},
 	}
 
 	for _, test := range tt {
@@ -9,6 +9,6 @@ import (
 type PackageInputService interface {
 	ScanBaseDeliveryCostPkgCount(clients.BaseWriter) (models.BaseDeliveryCost, int, error)
 	ScanNPackageDetails(clients.BaseWriter, int) ([]*models.PackageDetails, error)
-	ScanVehicleDetails(clients.BaseWriter) (int, int, int, error)
+	ScanVehicleDetails(clients.BaseWriter) (models.Fleet, error)
 	ScanProgramChoice(clients.BaseWriter) (string, error)
 }
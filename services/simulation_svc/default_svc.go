@@ -0,0 +1,55 @@
+package simulation_svc
+
+import (
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/services/delivery_svc"
+)
+
+type defaultService struct {
+	current  delivery_svc.DeliveryService
+	proposed delivery_svc.DeliveryService
+	options  Options
+}
+
+// Simulation of the proposed offers, delivery services differ by their offers only (same rate card)
+func NewSimulationService(current delivery_svc.DeliveryService, proposed delivery_svc.DeliveryService) SimulationService {
+	return NewSimulationServiceWithOptions(current, proposed, Options{})
+}
+
+func NewSimulationServiceWithOptions(current delivery_svc.DeliveryService, proposed delivery_svc.DeliveryService, options Options) SimulationService {
+	return &defaultService{
+		current:  current,
+		proposed: proposed,
+		options:  options,
+	}
+}
+
+func (s *defaultService) SimulateOffers(baseDeliveryCost models.BaseDeliveryCost, packages []*models.PackageDetails) (models.OfferSimulation, error) {
+	// simulated discounts are never redeemed
+	defer s.current.ReleaseRedemptions()
+	defer s.proposed.ReleaseRedemptions()
+
+	simulation := models.NewOfferSimulation(s.options.OfferCodes)
+	for _, pkg := range packages {
+		deliveryCost := s.current.CalculateDeliveryCost(pkg.Weight, pkg.Distance, baseDeliveryCost)
+		// est delivery time is not computed for the batch
+		fact := models.Fact{
+			Weight:           pkg.Weight,
+			Distance:         pkg.Distance,
+			BaseDeliveryCost: float64(baseDeliveryCost),
+			DeliveryCost:     deliveryCost.Float64(),
+			PackageCount:     len(packages),
+			Customer:         pkg.Customer,
+		}
+		current, err := s.current.CalculateDiscounts(fact, pkg.OfferCodes(), deliveryCost)
+		if err != nil {
+			return models.OfferSimulation{}, err
+		}
+		proposed, err := s.proposed.CalculateDiscounts(fact, pkg.OfferCodes(), deliveryCost)
+		if err != nil {
+			return models.OfferSimulation{}, err
+		}
+		simulation.Add(current, proposed)
+	}
+	return simulation, nil
+}
@@ -0,0 +1,88 @@
+package simulation_svc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/services/delivery_svc"
+	"github.com/lakshmaji/delivery-shell/services/offers_svc"
+)
+
+func deliveryService(offers []models.Offer, err error) delivery_svc.DeliveryService {
+	load := func(string) ([]models.Offer, error) {
+		return offers, err
+	}
+	return delivery_svc.NewDeliveryService(offers_svc.NewOffersService(load, "offers.json", models.RoundHalfUp), models.DefaultRateCard())
+}
+
+func TestSimulateOffers(t *testing.T) {
+	conditions := []models.Condition{{Fact: models.FactWeight, Operator: models.GreaterThanOrEqual, Value: 10}, {Fact: models.FactDistance, Operator: models.LessThan, Value: 250}}
+	current := deliveryService([]models.Offer{{Code: "OFR003", Discount: 0.05, Conditions: conditions}}, nil)
+	proposed := deliveryService([]models.Offer{
+		{Code: "OFR003", Discount: 0.1, Conditions: conditions},
+		{Code: "OFR009", Discount: 20, DiscountType: models.DiscountFlat, MaxRedemptions: 1, Conditions: []models.Condition{{Fact: models.FactWeight, Operator: models.LessThan, Value: 10}}},
+	}, nil)
+	packages := []*models.PackageDetails{
+		{Id: "PKG1", Weight: 5, Distance: 5, Code: "OFR009"},
+		{Id: "PKG2", Weight: 15, Distance: 5, Code: "OFR003"},
+		{Id: "PKG3", Weight: 10, Distance: 100, Code: "OFR003"},
+		{Id: "PKG4", Weight: 5, Distance: 5, Code: models.NoOfferCode},
+		// redemption limit of OFR009 is reached within the batch
+		{Id: "PKG5", Weight: 1, Distance: 5, Code: "OFR009"},
+	}
+
+	simulation, err := NewSimulationService(current, proposed).SimulateOffers(100, packages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Offer Code, Current Packages, Current Discount, Current Avg Discount, Proposed Packages, Proposed Discount, Proposed Avg Discount, Discount Difference\n" +
+		"OFR003, 2, 48.75, 24.38, 2, 97.50, 48.75, +48.75\n" +
+		"OFR009, 0, 0.00, 0.00, 1, 20.00, 20.00, +20.00\n" +
+		"Total (5 packages), 2, 48.75, 24.38, 3, 117.50, 39.17, +68.75\n"
+	if result := simulation.FmtOutput(); result != expected {
+		t.Errorf("expected\n%s\nreceived\n%s", expected, result)
+	}
+
+	// simulated redemptions are released, so the limit is not reached by a previous simulation
+	simulation, err = NewSimulationService(current, proposed).SimulateOffers(100, packages[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simulation.Proposed.Packages != 1 || simulation.Proposed.Discount != models.Money(20*models.MoneyScale) {
+		t.Errorf("expected OFR009 to be applied again, received %+v", simulation.Proposed)
+	}
+}
+
+func TestSimulateOffersListsEveryOfferCode(t *testing.T) {
+	conditions := []models.Condition{{Fact: models.FactWeight, Operator: models.LessThan, Value: 10}}
+	current := deliveryService([]models.Offer{{Code: "OFR002", Discount: 0.05, Conditions: conditions}}, nil)
+	proposed := deliveryService([]models.Offer{{Code: "OFR002", Discount: 0.1, Conditions: conditions}, {Code: "OFR001", Discount: 0.2, Conditions: conditions}}, nil)
+	options := Options{OfferCodes: []models.OfferCode{"OFR002", "OFR002", "OFR001"}}
+
+	simulation, err := NewSimulationServiceWithOptions(current, proposed, options).SimulateOffers(100, []*models.PackageDetails{
+		{Id: "PKG1", Weight: 5, Distance: 5, Code: "OFR002"},
+		{Id: "PKG2", Weight: 5, Distance: 5, Code: "OFR003"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Offer Code, Current Packages, Current Discount, Current Avg Discount, Proposed Packages, Proposed Discount, Proposed Avg Discount, Discount Difference\n" +
+		"OFR001, 0, 0.00, 0.00, 0, 0.00, 0.00, 0.00\n" +
+		"OFR002, 1, 8.75, 8.75, 1, 17.50, 17.50, +8.75\n" +
+		"Total (2 packages), 1, 8.75, 8.75, 1, 17.50, 17.50, +8.75\n"
+	if result := simulation.FmtOutput(); result != expected {
+		t.Errorf("expected\n%s\nreceived\n%s", expected, result)
+	}
+}
+
+func TestSimulateOffersErrors(t *testing.T) {
+	loadErr := errors.New("invalid offers")
+	current := deliveryService([]models.Offer{}, nil)
+	proposed := deliveryService(nil, loadErr)
+
+	_, err := NewSimulationService(current, proposed).SimulateOffers(100, []*models.PackageDetails{{Id: "PKG1", Weight: 5, Distance: 5, Code: "OFR001"}})
+	if !errors.Is(err, loadErr) {
+		t.Errorf("expected %v, received %v", loadErr, err)
+	}
+}
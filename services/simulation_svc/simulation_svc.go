@@ -0,0 +1,16 @@
+package simulation_svc
+
+import "github.com/lakshmaji/delivery-shell/models"
+
+type SimulationService interface {
+	// What the proposed offers would cost compared to the current offers, over a batch of packages
+	// every package is priced once, and its offer codes are evaluated against both offers
+	// redemptions are reserved for the batch only (limits apply within the batch), and never committed
+	SimulateOffers(baseDeliveryCost models.BaseDeliveryCost, packages []*models.PackageDetails) (models.OfferSimulation, error)
+}
+
+type Options struct {
+	// Offer codes of the current and the proposed offers, listed even when no package of the batch carries them
+	// only the codes carried by the packages are listed when not specified
+	OfferCodes []models.OfferCode
+}
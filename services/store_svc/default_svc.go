@@ -0,0 +1,35 @@
+package store_svc
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+type fileStore struct {
+	filename string
+	load     func(filename string) ([]models.Offer, error)
+}
+
+// Offers kept in a local file, decoded by the given loader (ex: offer_utils.LoadOffers)
+func NewFileStore(filename string, load func(string) ([]models.Offer, error)) OfferStore {
+	return &fileStore{filename: filename, load: load}
+}
+
+func (s *fileStore) List() ([]models.Offer, error) {
+	return s.load(s.filename)
+}
+
+// Modification time and size of the file
+func (s *fileStore) Version() (string, error) {
+	info, err := os.Stat(s.filename)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size()), nil
+}
+
+func (s *fileStore) Source() string {
+	return s.filename
+}
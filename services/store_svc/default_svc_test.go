@@ -0,0 +1,63 @@
+package store_svc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
+)
+
+const offersJSON = `[
+	{"code": "A", "discount": 0.1, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 20}]},
+	{"code": "B", "discount": 0.2, "conditions": [{"fact": "distance", "operator": "lessThan", "value": 10}]}
+]`
+
+func TestFileStore(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "offers.json")
+	if err := os.WriteFile(filename, []byte(offersJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileStore(filename, offer_utils.LoadOffers)
+
+	assertStore(t, store)
+	if store.Source() != filename {
+		t.Errorf("Source() = %v, want %v", store.Source(), filename)
+	}
+
+	version, err := store.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filename, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := store.Version(); changed == version {
+		t.Errorf("Version() should change when the file is modified, received %v", changed)
+	}
+
+	missing := NewFileStore(filepath.Join(t.TempDir(), "missing.json"), offer_utils.LoadOffers)
+	if _, err := missing.List(); err == nil {
+		t.Error("Should throw error")
+	}
+	if _, err := missing.Version(); err == nil {
+		t.Error("Should throw error")
+	}
+}
+
+// Offers A (10%) and B (20%) are expected in the store
+func assertStore(t *testing.T, store OfferStore) {
+	t.Helper()
+	offers, err := store.List()
+	if err != nil {
+		t.Fatalf("List() should not throw error, received %v", err)
+	}
+	if len(offers) != 2 || offers[0].Code != "A" || offers[1].Code != "B" {
+		t.Errorf("List() = %+v, want offers A and B", offers)
+	}
+	if offers[1].Discount != 0.2 {
+		t.Errorf("List() = %+v, want 20%% discount of offer B", offers)
+	}
+}
@@ -0,0 +1,100 @@
+package store_svc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
+)
+
+type httpStore struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	etag    string // of the last downloaded offers, sent as If-None-Match
+	content []byte // last downloaded offers, reused when the server responds 304 Not Modified
+}
+
+// Offers served by an http endpoint, which responds with the offers (json array) to GET requests
+// Requests are conditional (If-None-Match) once the endpoint responds with an ETag, so unchanged offers are not downloaded again
+// http.DefaultClient is used when client is nil
+func NewHTTPStore(url string, client *http.Client) OfferStore {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpStore{url: url, client: client}
+}
+
+func (s *httpStore) List() ([]models.Offer, error) {
+	content, _, err := s.fetch()
+	if err != nil {
+		return nil, err
+	}
+	offers, err := offer_utils.DecodeOffers(content)
+	if err != nil {
+		return nil, error_utils.ErrOfferStore(s.url, err)
+	}
+	return offers, nil
+}
+
+// ETag of the offers, otherwise digest of the response
+func (s *httpStore) Version() (string, error) {
+	content, etag, err := s.fetch()
+	if err != nil {
+		return "", err
+	}
+	if etag != "" {
+		return etag, nil
+	}
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+func (s *httpStore) Source() string {
+	return s.url
+}
+
+// Offers along with their ETag, the last downloaded offers when they are not modified since
+func (s *httpStore) fetch() ([]byte, string, error) {
+	request, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, "", error_utils.ErrOfferStore(s.url, err)
+	}
+	s.mu.Lock()
+	etag, cached := s.etag, s.content
+	s.mu.Unlock()
+	if etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, "", error_utils.ErrOfferStore(s.url, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotModified && etag != "" {
+		return cached, etag, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, "", error_utils.ErrOfferStoreStatus(s.url, response.StatusCode)
+	}
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", error_utils.ErrOfferStore(s.url, err)
+	}
+
+	etag = response.Header.Get("ETag")
+	s.mu.Lock()
+	s.etag, s.content = etag, content
+	if etag == "" {
+		s.content = nil
+	}
+	s.mu.Unlock()
+	return content, etag, nil
+}
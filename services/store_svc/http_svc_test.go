@@ -0,0 +1,101 @@
+package store_svc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestHTTPStore(t *testing.T) {
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/offers" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(offersJSON))
+	}))
+	defer server.Close()
+
+	store := NewHTTPStore(server.URL+"/offers", server.Client())
+	assertStore(t, store)
+	if version, err := store.Version(); err != nil || version != `"v1"` {
+		t.Errorf("Version() = %v %v, want ETag", version, err)
+	}
+	etag = ""
+	first, _ := store.Version()
+	second, _ := store.Version()
+	if first == "" || first != second {
+		t.Errorf("Version() without ETag should be a digest of the offers, received %q and %q", first, second)
+	}
+
+	missing := NewHTTPStore(server.URL+"/missing", nil)
+	if _, err := missing.List(); err == nil || err.Error() != "Offer store "+server.URL+"/missing error: unexpected status 404" {
+		t.Errorf("List() error = %v, want unexpected status", err)
+	}
+}
+
+func TestHTTPStoreNotModified(t *testing.T) {
+	var mu sync.Mutex
+	etag, downloads := `"v1"`, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Write([]byte(offersJSON))
+	}))
+	defer server.Close()
+	downloaded := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return downloads
+	}
+
+	store := NewHTTPStore(server.URL, server.Client())
+	// polling unchanged offers, and listing them, downloads them once
+	for i := 0; i < 3; i++ {
+		if version, err := store.Version(); err != nil || version != `"v1"` {
+			t.Errorf("Version() = %v %v, want ETag", version, err)
+		}
+	}
+	assertStore(t, store)
+	if count := downloaded(); count != 1 {
+		t.Errorf("expected offers to be downloaded once, received %d downloads", count)
+	}
+
+	mu.Lock()
+	etag = `"v2"`
+	mu.Unlock()
+	if version, err := store.Version(); err != nil || version != `"v2"` {
+		t.Errorf("Version() = %v %v, want new ETag", version, err)
+	}
+	if offers, err := store.List(); err != nil || len(offers) == 0 {
+		t.Errorf("List() = %v %v, want offers", offers, err)
+	}
+	if count := downloaded(); count != 2 {
+		t.Errorf("expected modified offers to be downloaded once, received %d downloads", count)
+	}
+}
+
+func TestHTTPStoreInvalidOffers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"code": "A"}]`))
+	}))
+	defer server.Close()
+
+	if _, err := NewHTTPStore(server.URL, nil).List(); err == nil {
+		t.Error("Should throw error")
+	}
+
+	server.Close()
+	if _, err := NewHTTPStore(server.URL, nil).Version(); err == nil {
+		t.Error("unreachable store should throw error")
+	}
+}
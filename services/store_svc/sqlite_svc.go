@@ -0,0 +1,85 @@
+package store_svc
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+	"github.com/lakshmaji/delivery-shell/utils/offer_utils"
+
+	// embedded sqlite (pure go)
+	_ "modernc.org/sqlite"
+)
+
+// Every offer is a row, offer column holds the offer as in offers.json (ex: {"code": "OFR001", "discount": 0.1, ...})
+const SQLiteSchema = `CREATE TABLE IF NOT EXISTS offers (
+	code  TEXT PRIMARY KEY,
+	offer TEXT NOT NULL
+)`
+
+type sqliteStore struct {
+	path string
+	db   *sql.DB
+}
+
+// Offers kept in an embedded SQLite database, offers table is created when it does not exist
+func NewSQLiteStore(path string) (OfferStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, error_utils.ErrOfferStore(path, err)
+	}
+	// data_version is tracked per connection
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(SQLiteSchema); err != nil {
+		db.Close()
+		return nil, error_utils.ErrOfferStore(path, err)
+	}
+	return &sqliteStore{path: path, db: db}, nil
+}
+
+func (s *sqliteStore) List() ([]models.Offer, error) {
+	rows, err := s.db.Query(`SELECT code, offer FROM offers ORDER BY rowid`)
+	if err != nil {
+		return nil, error_utils.ErrOfferStore(s.path, err)
+	}
+	defer rows.Close()
+
+	codes, offers := []string{}, []string{}
+	for rows.Next() {
+		var code, offer string
+		if err := rows.Scan(&code, &offer); err != nil {
+			return nil, error_utils.ErrOfferStore(s.path, err)
+		}
+		codes, offers = append(codes, code), append(offers, offer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, error_utils.ErrOfferStore(s.path, err)
+	}
+	// validated the same way as an offers file
+	decoded, err := offer_utils.DecodeOffers([]byte("[" + strings.Join(offers, ",\n") + "]"))
+	if err != nil {
+		return nil, error_utils.ErrOfferStore(s.path, err)
+	}
+	// code column is the key of the row, it should be the code of the offer kept in the row
+	for i, offer := range decoded {
+		if string(offer.Code) != codes[i] {
+			return nil, error_utils.ErrOfferStore(s.path, error_utils.ErrOfferStoreCode(codes[i], offer.Code))
+		}
+	}
+	return decoded, nil
+}
+
+// Changes whenever another connection (process) modifies the database
+func (s *sqliteStore) Version() (string, error) {
+	var version int64
+	if err := s.db.QueryRow(`PRAGMA data_version`).Scan(&version); err != nil {
+		return "", error_utils.ErrOfferStore(s.path, err)
+	}
+	return fmt.Sprint(version), nil
+}
+
+func (s *sqliteStore) Source() string {
+	return s.path
+}
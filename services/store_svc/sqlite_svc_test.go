@@ -0,0 +1,58 @@
+package store_svc
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offers.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.List(); err == nil {
+		t.Error("empty store should throw error (at least 1 offer)")
+	}
+	version, err := store.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// offers are managed by another process
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	insert := func(code string, offer string) {
+		t.Helper()
+		if _, err := db.Exec(`INSERT INTO offers (code, offer) VALUES (?, ?)`, code, offer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert("A", `{"code": "A", "discount": 0.1, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 20}]}`)
+	insert("B", `{"code": "B", "discount": 0.2, "conditions": [{"fact": "distance", "operator": "lessThan", "value": 10}]}`)
+
+	assertStore(t, store)
+	if changed, _ := store.Version(); changed == version {
+		t.Errorf("Version() should change when offers are modified, received %v", changed)
+	}
+	if store.Source() != path {
+		t.Errorf("Source() = %v, want %v", store.Source(), path)
+	}
+
+	insert("C", `{"code": "C", "discount": 2, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 20}]}`)
+	if _, err := store.List(); err == nil {
+		t.Error("invalid offer should throw error")
+	}
+
+	if _, err := db.Exec(`DELETE FROM offers WHERE code = 'C'`); err != nil {
+		t.Fatal(err)
+	}
+	insert("D", `{"code": "E", "discount": 0.1, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 20}]}`)
+	if _, err := store.List(); err == nil || err.Error() != "Offer store "+path+" error: offer of row \"D\" has code \"E\"" {
+		t.Errorf("List() error = %v, want offer code different from the row code", err)
+	}
+}
@@ -0,0 +1,14 @@
+package store_svc
+
+import "github.com/lakshmaji/delivery-shell/models"
+
+// Source of offers (file, database, http endpoint etc.), offers are validated before they are handed out
+// offers are looked up by code on the index kept by the offer service, stores only list them
+type OfferStore interface {
+	// Every offer, in order of definition
+	List() ([]models.Offer, error)
+	// Identifies the current revision of the offers, changes whenever the offers change
+	Version() (string, error)
+	// Where offers are loaded from, ex: offers.json, https://example.com/offers
+	Source() string
+}
@@ -2,20 +2,22 @@ package delivery_utils
 
 import (
 	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
 )
 
 type testCase struct {
-	deliveryCost      float64
-	discount          float64
-	totalDeliveryCost float64
+	deliveryCost      models.Money
+	discount          models.Money
+	totalDeliveryCost models.Money
 	description       string
 }
 
 var calculateDeliveryCostTests = []testCase{
-	{deliveryCost: 10, discount: 0.01, totalDeliveryCost: 9.99, description: "when both inputs are decimals"},
-	{deliveryCost: 10, discount: 3, totalDeliveryCost: 7, description: "when both inputs are integers"},
+	{deliveryCost: 1000, discount: 1, totalDeliveryCost: 999, description: "when both inputs are decimals"},
+	{deliveryCost: 1000, discount: 300, totalDeliveryCost: 700, description: "when both inputs are whole amounts"},
 	{deliveryCost: 0, discount: 0, totalDeliveryCost: 0, description: "when both inputs are 0"},
-	{deliveryCost: 1, discount: 3, totalDeliveryCost: 0, description: "when delivery cost is less than discount"},
+	{deliveryCost: 100, discount: 300, totalDeliveryCost: 0, description: "when delivery cost is less than discount"},
 }
 
 func TestCalculateTotalDeliveryCost(t *testing.T) {
@@ -0,0 +1,54 @@
+package delivery_utils
+
+import "github.com/lakshmaji/delivery-shell/models"
+
+// Charges for the given fact value as per the slab pricing.
+//
+// Pre-conditions: slabs are contiguous, starts at 0 and the last slab is open ended (validated by rate card loader)
+//
+// cumulative - every slab charges the portion of the value which falls in that slab
+// flat - the slab containing the value charges the whole value
+//
+// Every slab amount is rounded to the nearest minor unit using the given mode
+func SlabCharges(fact string, value float64, pricing models.SlabPricing, mode models.RoundingMode) []models.SlabCharge {
+	var charges []models.SlabCharge
+	for _, slab := range pricing.Slabs {
+		isOpenEnded := slab.To == 0
+		switch pricing.Mode {
+		case models.SlabModeFlat:
+			if value >= slab.From && (isOpenEnded || value < slab.To) {
+				return append(charges, slabCharge(fact, slab, value, mode))
+			}
+		default:
+			if value <= slab.From && slab.From != 0 {
+				return charges
+			}
+			units := value - slab.From
+			if !isOpenEnded && value > slab.To {
+				units = slab.To - slab.From
+			}
+			charges = append(charges, slabCharge(fact, slab, units, mode))
+		}
+	}
+	return charges
+}
+
+func slabCharge(fact string, slab models.Slab, units float64, mode models.RoundingMode) models.SlabCharge {
+	return models.SlabCharge{
+		Fact:   fact,
+		From:   slab.From,
+		To:     slab.To,
+		Units:  units,
+		Rate:   slab.Rate,
+		Amount: models.MoneyOf(units, slab.Rate, mode),
+	}
+}
+
+// Sum of all slab charges
+func SlabChargesTotal(charges []models.SlabCharge) models.Money {
+	var total models.Money
+	for _, charge := range charges {
+		total = total.Add(charge.Amount)
+	}
+	return total
+}
@@ -0,0 +1,86 @@
+package delivery_utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+var weightSlabs = []models.Slab{
+	{From: 0, To: 5, Rate: 10},
+	{From: 5, To: 20, Rate: 8},
+	{From: 20, Rate: 6},
+}
+
+func TestSlabCharges(t *testing.T) {
+	tt := []struct {
+		description string
+		value       float64
+		pricing     models.SlabPricing
+		expected    []models.SlabCharge
+	}{
+		{
+			description: "linear rate",
+			value:       15,
+			pricing:     models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: []models.Slab{{Rate: 10}}},
+			expected:    []models.SlabCharge{{Fact: "weight", Units: 15, Rate: 10, Amount: 15000}},
+		},
+		{
+			description: "cumulative within first slab",
+			value:       3,
+			pricing:     models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: weightSlabs},
+			expected:    []models.SlabCharge{{Fact: "weight", From: 0, To: 5, Units: 3, Rate: 10, Amount: 3000}},
+		},
+		{
+			description: "cumulative across slabs",
+			value:       25,
+			pricing:     models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: weightSlabs},
+			expected: []models.SlabCharge{
+				{Fact: "weight", From: 0, To: 5, Units: 5, Rate: 10, Amount: 5000},
+				{Fact: "weight", From: 5, To: 20, Units: 15, Rate: 8, Amount: 12000},
+				{Fact: "weight", From: 20, Units: 5, Rate: 6, Amount: 3000},
+			},
+		},
+		{
+			description: "cumulative on slab boundary",
+			value:       5,
+			pricing:     models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: weightSlabs},
+			expected:    []models.SlabCharge{{Fact: "weight", From: 0, To: 5, Units: 5, Rate: 10, Amount: 5000}},
+		},
+		{
+			description: "rounds slab amount",
+			value:       0.125,
+			pricing:     models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: []models.Slab{{Rate: 1}}},
+			expected:    []models.SlabCharge{{Fact: "weight", Units: 0.125, Rate: 1, Amount: 13}},
+		},
+		{
+			description: "flat by band",
+			value:       12,
+			pricing:     models.SlabPricing{Mode: models.SlabModeFlat, Slabs: weightSlabs},
+			expected:    []models.SlabCharge{{Fact: "weight", From: 5, To: 20, Units: 12, Rate: 8, Amount: 9600}},
+		},
+		{
+			description: "flat on slab boundary picks the next band",
+			value:       20,
+			pricing:     models.SlabPricing{Mode: models.SlabModeFlat, Slabs: weightSlabs},
+			expected:    []models.SlabCharge{{Fact: "weight", From: 20, Units: 20, Rate: 6, Amount: 12000}},
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.description, func(t *testing.T) {
+			charges := SlabCharges("weight", test.value, test.pricing, models.RoundHalfUp)
+			if !reflect.DeepEqual(charges, test.expected) {
+				t.Errorf("\t\tExpected: %v, got: %v", test.expected, charges)
+			}
+		})
+	}
+}
+
+func TestSlabChargesTotal(t *testing.T) {
+	charges := SlabCharges("weight", 25, models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: weightSlabs}, models.RoundHalfUp)
+	if total := SlabChargesTotal(charges); total != 20000 {
+		t.Errorf("\t\tExpected: %v, got: %v", 20000, total)
+	}
+}
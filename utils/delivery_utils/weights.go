@@ -0,0 +1,22 @@
+package delivery_utils
+
+import (
+	"math"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+// Weights within this many units of a whole unit are treated as whole (float noise, ex: 1.1 / 0.1 = 11.000000000000002)
+const unitTolerance = 1e-9
+
+// Package weight in whole units of the resolution, rounded up so that the package is never underweighted
+// ex: 49.9 kg at 0.1 kg resolution is 499 units, 49.95 kg is 500 units
+func WeightUnits(weight models.Weight, resolution models.Weight) int {
+	return int(math.Ceil(weight/resolution - unitTolerance))
+}
+
+// Vehicle capacity in whole units of the resolution, rounded down so that a shipment never exceeds the capacity
+// ex: 200.5 kg at 1 kg resolution is 200 units
+func CapacityUnits(capacity models.Weight, resolution models.Weight) int {
+	return int(math.Floor(capacity/resolution + unitTolerance))
+}
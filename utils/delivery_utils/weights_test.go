@@ -0,0 +1,35 @@
+package delivery_utils
+
+import (
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+func TestWeightUnits(t *testing.T) {
+	tt := []struct {
+		description string
+		weight      models.Weight
+		resolution  models.Weight
+		weightUnits int
+		capacity    int
+	}{
+		{description: "whole weight", weight: 50, resolution: 1, weightUnits: 50, capacity: 50},
+		{description: "fractional weight at 1 kg", weight: 49.9, resolution: 1, weightUnits: 50, capacity: 49},
+		{description: "fractional weight at 0.1 kg", weight: 49.9, resolution: 0.1, weightUnits: 499, capacity: 499},
+		{description: "float noise", weight: 1.1, resolution: 0.1, weightUnits: 11, capacity: 11},
+		{description: "finer than resolution", weight: 49.95, resolution: 0.1, weightUnits: 500, capacity: 499},
+		{description: "at 0.01 kg", weight: 0.07, resolution: 0.01, weightUnits: 7, capacity: 7},
+		{description: "zero", weight: 0, resolution: 0.01, weightUnits: 0, capacity: 0},
+	}
+	for _, test := range tt {
+		t.Run(test.description, func(t *testing.T) {
+			if result := WeightUnits(test.weight, test.resolution); result != test.weightUnits {
+				t.Errorf("expected weight units %d received %d", test.weightUnits, result)
+			}
+			if result := CapacityUnits(test.weight, test.resolution); result != test.capacity {
+				t.Errorf("expected capacity units %d received %d", test.capacity, result)
+			}
+		})
+	}
+}
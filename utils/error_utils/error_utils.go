@@ -3,6 +3,7 @@ package error_utils
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/lakshmaji/delivery-shell/models"
 )
@@ -10,14 +11,143 @@ import (
 var (
 	ErrMissingInput          = errors.New("Missing input")
 	ErrBaseCostPkgCount      = errors.New("Format Error:  \"base delivery cost\" and \"No of packages\" separated by space delimiter")
-	ErrPackageDetailsFormat  = errors.New("Format Error: \"box_id\" \"box_weight_in_kg\" \"distance_in_km\" \"offer_code\"")
+	ErrPackageDetailsFormat  = errors.New("Format Error: \"box_id\" \"box_weight_in_kg\" \"distance_in_km\" \"offer_code\" [\"customer_id\"]")
 	ErrVehicleDetailsFormat  = errors.New("Format Error: \"vehicles count\" \"speed\" \"weight capacity\"")
 	ErrProgramChoiceFormat   = errors.New("Format Error: enter one of them yes, no")
 	ErrPackageDetailsInValid = errors.New("Package weight wont be considered for delivery")
 	ErrCalculateDiscount     = errors.New("Error while applying discount")
+	ErrRateCardName          = errors.New("Rate card error: \"name\" is required")
+	ErrOffersWatchInterval   = errors.New("OFFERS_WATCH_INTERVAL should be a positive duration (ex: 30s)")
+	ErrOffersLint            = errors.New("Offers have lint errors")
+	ErrUnknownOfferCode      = errors.New("Unknown offer code")
+	ErrRedemptionLimit       = errors.New("Redemption limit reached")
+	ErrCustomerLimit         = errors.New("Redemption limit of the customer reached")
+	ErrCustomerRequired      = errors.New("Customer is required for per customer redemption limit")
+	ErrSimulateOffersUsage   = errors.New("Usage: simulate-offers batch_file current_offers_file proposed_offers_file")
+	ErrVehicleFormat         = errors.New("Format Error: \"vehicle id\" \"type\" \"weight capacity\" \"speed\" \"available at\" (optional)")
+	ErrFleetEmpty            = errors.New("Fleet error: at least one vehicle is required")
+	ErrManifestFile          = errors.New("Json manifest is written to a file, set --manifest-file")
 )
 
-func ErrVehicleMaxWeightCapacity(box *models.PackageDetails, maxWeight int) error {
+func ErrVehicleMaxWeightCapacity(box *models.PackageDetails, maxWeight models.Weight) error {
 	//nolint:gosimple
-	return errors.New(fmt.Sprintf("Box %s weight %f exceed vehicle max weight capacity of %d", box.Id, box.Weight, maxWeight))
+	return errors.New(fmt.Sprintf("Box %s weight %f exceed vehicle max weight capacity of %v", box.Id, box.Weight, maxWeight))
+}
+
+// One line per package heavier than every vehicle
+func ErrVehiclesMaxWeightCapacity(boxes []*models.PackageDetails, maxWeight models.Weight) error {
+	lines := make([]string, len(boxes))
+	for i, box := range boxes {
+		lines[i] = ErrVehicleMaxWeightCapacity(box, maxWeight).Error()
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+// Packages left out of every planned trip
+func ErrPackagesNotShipped(boxes []*models.PackageDetails) error {
+	ids := make([]string, len(boxes))
+	for i, box := range boxes {
+		ids[i] = string(box.Id)
+	}
+	return fmt.Errorf("Packages %s could not be shipped by any vehicle", strings.Join(ids, ", "))
+}
+
+func ErrRateCardNegativeValue(field string, value float64) error {
+	return fmt.Errorf("Rate card error: %q should not be negative, received %v", field, value)
+}
+
+func ErrRateCardSlabMode(fact string, mode string) error {
+	return fmt.Errorf("Rate card error: unknown %s slabs mode %q, expected one of cumulative, flat", fact, mode)
+}
+
+func ErrRateCardSlab(fact string, index int, reason string) error {
+	return fmt.Errorf("Rate card error: %s slab %d %s", fact, index, reason)
+}
+
+func ErrRateCardRounding(mode models.RoundingMode) error {
+	return fmt.Errorf("Rate card error: unknown rounding mode %q, expected one of halfUp, halfEven, truncate", mode)
+}
+
+func ErrOfferUnknownOperator(code models.OfferCode, operator string) error {
+	return fmt.Errorf("Offer %s error: unknown operator %q", code, operator)
+}
+
+func ErrOfferConditionValues(code models.OfferCode, operator string, reason string) error {
+	return fmt.Errorf("Offer %s error: %s condition %s", code, operator, reason)
+}
+
+func ErrOfferRule(code models.OfferCode, reason string) error {
+	return fmt.Errorf("Offer %s error: %s", code, reason)
+}
+
+func ErrOfferDiscount(code models.OfferCode, reason string) error {
+	return fmt.Errorf("Offer %s error: %s", code, reason)
+}
+
+func ErrOfferStacking(code models.OfferCode, reason string) error {
+	return fmt.Errorf("Offer %s error: %s", code, reason)
+}
+
+func ErrOfferRedemptionLimit(code models.OfferCode, reason string) error {
+	return fmt.Errorf("Offer %s error: %s", code, reason)
+}
+
+func ErrOfferSchedule(code models.OfferCode, reason string) error {
+	return fmt.Errorf("Offer %s error: %s", code, reason)
+}
+
+func ErrOfferUnknownFact(code models.OfferCode, fact string) error {
+	return fmt.Errorf("Offer %s error: unknown fact %q", code, fact)
+}
+
+func ErrFactDefinition(fact string) error {
+	return fmt.Errorf("Fact %q error: kind should be number or text, along with its resolver", fact)
+}
+
+func ErrFactRegistered(fact string) error {
+	return fmt.Errorf("Fact %q error: already registered", fact)
+}
+
+// Unknown offer code (strict mode), along with the closest known code when there is one
+func ErrOfferCodeUnknown(code models.OfferCode, suggestion models.OfferCode) error {
+	if suggestion == "" {
+		return fmt.Errorf("%w %s", ErrUnknownOfferCode, code)
+	}
+	return fmt.Errorf("%w %s, did you mean %s?", ErrUnknownOfferCode, code, suggestion)
+}
+
+func ErrPackageOffer(id models.PackageID, err error) error {
+	return fmt.Errorf("Package %s error: %w", id, err)
+}
+
+func ErrLedger(filename string, err error) error {
+	return fmt.Errorf("Redemption ledger %s error: %w", filename, err)
+}
+
+func ErrOfferStore(source string, err error) error {
+	return fmt.Errorf("Offer store %s error: %w", source, err)
+}
+
+func ErrOfferStoreStatus(source string, status int) error {
+	return fmt.Errorf("Offer store %s error: unexpected status %d", source, status)
+}
+
+func ErrOfferStoreCode(key string, code models.OfferCode) error {
+	return fmt.Errorf("offer of row %q has code %q", key, code)
+}
+
+func ErrOfferStoreKind(kind string) error {
+	return fmt.Errorf("OFFERS_STORE should be one of file, sqlite, http, received %q", kind)
+}
+
+func ErrShipmentStrategy(name string, names []string) error {
+	return fmt.Errorf("Shipment strategy should be one of %s, received %q", strings.Join(names, ", "), name)
+}
+
+func ErrFleetVehicle(index int, reason string) error {
+	return fmt.Errorf("Fleet error: vehicle %d %s", index, reason)
+}
+
+func ErrManifestFormat(format string) error {
+	return fmt.Errorf("Manifest format should be one of text, json, received %q", format)
 }
@@ -1,6 +1,7 @@
 package error_utils
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -16,7 +17,7 @@ func TestErrors(t *testing.T) {
 		t.Error("Value changed")
 	}
 
-	if ErrPackageDetailsFormat.Error() != "Format Error: \"box_id\" \"box_weight_in_kg\" \"distance_in_km\" \"offer_code\"" {
+	if ErrPackageDetailsFormat.Error() != "Format Error: \"box_id\" \"box_weight_in_kg\" \"distance_in_km\" \"offer_code\" [\"customer_id\"]" {
 		t.Error("Value changed")
 	}
 
@@ -32,8 +33,8 @@ func TestErrors(t *testing.T) {
 		Id:     "PKG 1",
 		Weight: 26,
 	}
-	maxWeight := 20
-	if ErrVehicleMaxWeightCapacity(box, maxWeight).Error() != fmt.Sprintf("Box %s weight %f exceed vehicle max weight capacity of %d", box.Id, box.Weight, maxWeight) {
+	maxWeight := models.Weight(20.5)
+	if ErrVehicleMaxWeightCapacity(box, maxWeight).Error() != fmt.Sprintf("Box %s weight %f exceed vehicle max weight capacity of %v", box.Id, box.Weight, maxWeight) {
 		t.Error("Value changed")
 	}
 
@@ -41,4 +42,156 @@ func TestErrors(t *testing.T) {
 		t.Error("Value changed")
 	}
 
+	if ErrOffersWatchInterval.Error() != "OFFERS_WATCH_INTERVAL should be a positive duration (ex: 30s)" {
+		t.Error("Value changed")
+	}
+
+	if ErrOffersLint.Error() != "Offers have lint errors" {
+		t.Error("Value changed")
+	}
+
+	if ErrSimulateOffersUsage.Error() != "Usage: simulate-offers batch_file current_offers_file proposed_offers_file" {
+		t.Error("Value changed")
+	}
+
+	if ErrRateCardName.Error() != "Rate card error: \"name\" is required" {
+		t.Error("Value changed")
+	}
+
+	if ErrRateCardNegativeValue("perKg", -1).Error() != "Rate card error: \"perKg\" should not be negative, received -1" {
+		t.Error("Value changed")
+	}
+
+	if ErrRateCardSlabMode("weight", "step").Error() != "Rate card error: unknown weight slabs mode \"step\", expected one of cumulative, flat" {
+		t.Error("Value changed")
+	}
+
+	if ErrRateCardRounding("ceil").Error() != "Rate card error: unknown rounding mode \"ceil\", expected one of halfUp, halfEven, truncate" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferUnknownOperator("OFR001", "greaterThanOrEqualTo").Error() != "Offer OFR001 error: unknown operator \"greaterThanOrEqualTo\"" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferConditionValues("OFR001", "between", "requires lower and upper bounds").Error() != "Offer OFR001 error: between condition requires lower and upper bounds" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferRule("OFR001", "requires conditions or rules").Error() != "Offer OFR001 error: requires conditions or rules" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferDiscount("OFR001", "minDiscount should not exceed maxDiscount").Error() != "Offer OFR001 error: minDiscount should not exceed maxDiscount" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferStacking("OFR001", "unknown stacking \"all\", expected one of exclusive, stackable, bestOf").Error() != "Offer OFR001 error: unknown stacking \"all\", expected one of exclusive, stackable, bestOf" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferSchedule("OFR001", "validFrom should be before validUntil").Error() != "Offer OFR001 error: validFrom should be before validUntil" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferUnknownFact("OFR001", "volume").Error() != "Offer OFR001 error: unknown fact \"volume\"" {
+		t.Error("Value changed")
+	}
+
+	if ErrFactDefinition("volume").Error() != "Fact \"volume\" error: kind should be number or text, along with its resolver" {
+		t.Error("Value changed")
+	}
+
+	if ErrFactRegistered("weight").Error() != "Fact \"weight\" error: already registered" {
+		t.Error("Value changed")
+	}
+
+	if ErrRateCardSlab("weight", 1, "should be open ended").Error() != "Rate card error: weight slab 1 should be open ended" {
+		t.Error("Value changed")
+	}
+
+	if ErrUnknownOfferCode.Error() != "Unknown offer code" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferCodeUnknown("OFR0O1", "OFR001").Error() != "Unknown offer code OFR0O1, did you mean OFR001?" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferCodeUnknown("SUMMER", "").Error() != "Unknown offer code SUMMER" {
+		t.Error("Value changed")
+	}
+
+	if !errors.Is(ErrOfferCodeUnknown("SUMMER", ""), ErrUnknownOfferCode) {
+		t.Error("should wrap ErrUnknownOfferCode")
+	}
+
+	if ErrPackageOffer("PKG1", ErrOfferCodeUnknown("SUMMER", "")).Error() != "Package PKG1 error: Unknown offer code SUMMER" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferRedemptionLimit("OFR001", "redemption limits should not be negative").Error() != "Offer OFR001 error: redemption limits should not be negative" {
+		t.Error("Value changed")
+	}
+
+	if ErrCustomerLimit.Error() != "Redemption limit of the customer reached" || ErrRedemptionLimit.Error() != "Redemption limit reached" {
+		t.Error("Value changed")
+	}
+
+	if ErrCustomerRequired.Error() != "Customer is required for per customer redemption limit" {
+		t.Error("Value changed")
+	}
+
+	if ErrLedger("redemptions.json", ErrRedemptionLimit).Error() != "Redemption ledger redemptions.json error: Redemption limit reached" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferStore("offers.db", ErrMissingInput).Error() != "Offer store offers.db error: Missing input" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferStoreStatus("http://localhost/offers", 500).Error() != "Offer store http://localhost/offers error: unexpected status 500" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferStoreCode("OFR001", "OFR002").Error() != "offer of row \"OFR001\" has code \"OFR002\"" {
+		t.Error("Value changed")
+	}
+
+	if ErrOfferStoreKind("redis").Error() != "OFFERS_STORE should be one of file, sqlite, http, received \"redis\"" {
+		t.Error("Value changed")
+	}
+
+	if ErrShipmentStrategy("fastest", []string{"max-load", "nearest-first"}).Error() != "Shipment strategy should be one of max-load, nearest-first, received \"fastest\"" {
+		t.Error("Value changed")
+	}
+
+	if ErrVehicleFormat.Error() != "Format Error: \"vehicle id\" \"type\" \"weight capacity\" \"speed\" \"available at\" (optional)" {
+		t.Error("Value changed")
+	}
+
+	if ErrFleetEmpty.Error() != "Fleet error: at least one vehicle is required" {
+		t.Error("Value changed")
+	}
+
+	if ErrFleetVehicle(1, "speed should be positive").Error() != "Fleet error: vehicle 1 speed should be positive" {
+		t.Error("Value changed")
+	}
+
+	boxes := []*models.PackageDetails{{Id: "PKG1", Weight: 250}, {Id: "PKG2", Weight: 300}}
+	if ErrVehiclesMaxWeightCapacity(boxes, 200).Error() != "Box PKG1 weight 250.000000 exceed vehicle max weight capacity of 200\nBox PKG2 weight 300.000000 exceed vehicle max weight capacity of 200" {
+		t.Error("Value changed")
+	}
+
+	if ErrPackagesNotShipped(boxes).Error() != "Packages PKG1, PKG2 could not be shipped by any vehicle" {
+		t.Error("Value changed")
+	}
+
+	if ErrManifestFormat("xml").Error() != "Manifest format should be one of text, json, received \"xml\"" {
+		t.Error("Value changed")
+	}
+
+	if ErrManifestFile.Error() != "Json manifest is written to a file, set --manifest-file" {
+		t.Error("Value changed")
+	}
 }
@@ -0,0 +1,72 @@
+package fleet_utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+// Every vehicle needs a unique id, positive (finite) capacity and speed, availability can not be before the start
+func ValidateFleet(fleet models.Fleet) error {
+	if len(fleet) == 0 {
+		return error_utils.ErrFleetEmpty
+	}
+	ids := make(map[models.VehicleID]bool)
+	for i, vehicle := range fleet {
+		if len(strings.TrimSpace(string(vehicle.Id))) == 0 {
+			return error_utils.ErrFleetVehicle(i, "id is required")
+		}
+		if ids[vehicle.Id] {
+			return error_utils.ErrFleetVehicle(i, fmt.Sprintf("duplicate id %q", vehicle.Id))
+		}
+		ids[vehicle.Id] = true
+		for _, value := range []struct {
+			field string
+			value float64
+		}{{"capacity", vehicle.Capacity}, {"speed", vehicle.Speed}, {"availableAt", vehicle.AvailableAt}} {
+			if math.IsNaN(value.value) || math.IsInf(value.value, 0) {
+				return error_utils.ErrFleetVehicle(i, fmt.Sprintf("%s should be a finite number", value.field))
+			}
+		}
+		if vehicle.Capacity <= 0 {
+			return error_utils.ErrFleetVehicle(i, "capacity should be positive")
+		}
+		if vehicle.Speed <= 0 {
+			return error_utils.ErrFleetVehicle(i, "speed should be positive")
+		}
+		if vehicle.AvailableAt < 0 {
+			return error_utils.ErrFleetVehicle(i, "availableAt should not be negative")
+		}
+	}
+	return nil
+}
+
+// Vehicles of the fleet file (json), ex: [{"id": "BIKE1", "type": "bike", "capacity": 20, "speed": 40, "availableAt": 0}]
+func LoadFleet(filename string) (models.Fleet, error) {
+	if len(strings.TrimSpace(filename)) == 0 {
+		return nil, error_utils.ErrMissingInput
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var fleet models.Fleet
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	// unknown keys are most likely typos of a vehicle attribute, which should not be ignored
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&fleet); err != nil {
+		return nil, err
+	}
+
+	if err = ValidateFleet(fleet); err != nil {
+		return nil, err
+	}
+	return fleet, nil
+}
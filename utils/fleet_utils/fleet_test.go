@@ -0,0 +1,140 @@
+package fleet_utils
+
+import (
+	"errors"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+func TestValidateFleet(t *testing.T) {
+	tt := []struct {
+		desc     string
+		fleet    models.Fleet
+		expected error
+	}{
+		{
+			desc:  "fleet of identical vehicles",
+			fleet: models.NewFleet(2, 70, 200),
+		},
+		{
+			desc:     "when fleet is empty",
+			fleet:    models.Fleet{},
+			expected: error_utils.ErrFleetEmpty,
+		},
+		{
+			desc:     "when id is missing",
+			fleet:    models.Fleet{{Capacity: 100, Speed: 60}},
+			expected: error_utils.ErrFleetVehicle(0, "id is required"),
+		},
+		{
+			desc:     "when id is repeated",
+			fleet:    models.Fleet{{Id: "VAN1", Capacity: 100, Speed: 60}, {Id: "VAN1", Capacity: 100, Speed: 60}},
+			expected: error_utils.ErrFleetVehicle(1, `duplicate id "VAN1"`),
+		},
+		{
+			desc:     "when capacity is not positive",
+			fleet:    models.Fleet{{Id: "VAN1", Speed: 60}},
+			expected: error_utils.ErrFleetVehicle(0, "capacity should be positive"),
+		},
+		{
+			desc:     "when speed is not positive",
+			fleet:    models.Fleet{{Id: "VAN1", Capacity: 100, Speed: -60}},
+			expected: error_utils.ErrFleetVehicle(0, "speed should be positive"),
+		},
+		{
+			desc:     "when capacity is infinite",
+			fleet:    models.Fleet{{Id: "VAN1", Capacity: math.Inf(1), Speed: 60}},
+			expected: error_utils.ErrFleetVehicle(0, "capacity should be a finite number"),
+		},
+		{
+			desc:     "when speed is not a number",
+			fleet:    models.Fleet{{Id: "VAN1", Capacity: 100, Speed: math.NaN()}},
+			expected: error_utils.ErrFleetVehicle(0, "speed should be a finite number"),
+		},
+		{
+			desc:     "when availability is infinite",
+			fleet:    models.Fleet{{Id: "VAN1", Capacity: 100, Speed: 60, AvailableAt: math.Inf(1)}},
+			expected: error_utils.ErrFleetVehicle(0, "availableAt should be a finite number"),
+		},
+		{
+			desc:     "when availability is negative",
+			fleet:    models.Fleet{{Id: "VAN1", Capacity: 100, Speed: 60, AvailableAt: -1}},
+			expected: error_utils.ErrFleetVehicle(0, "availableAt should not be negative"),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateFleet(test.fleet)
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestLoadFleet(t *testing.T) {
+	tt := []struct {
+		desc        string
+		file        string
+		expectedErr error
+		expected    models.Fleet
+	}{
+		{
+			desc:        "when fleet file is empty",
+			file:        "",
+			expectedErr: error_utils.ErrMissingInput,
+		},
+		{
+			desc: "when fleet file is valid",
+			file: "./testdata/fleet.json",
+			expected: models.Fleet{
+				{Id: "BIKE1", Type: "bike", Capacity: 20, Speed: 40},
+				{Id: "VAN1", Type: "van", Capacity: 100, Speed: 60},
+				{Id: "TRUCK1", Type: "truck", Capacity: 300, Speed: 50, AvailableAt: 1},
+			},
+		},
+		{
+			desc:        "when fleet file is not available",
+			file:        "./testdata/no_fleet.json",
+			expectedErr: &os.PathError{Op: "open", Path: "./testdata/no_fleet.json", Err: errors.New("no such file or directory")},
+		},
+		{
+			desc:        "when fleet has unknown fields",
+			file:        "./testdata/unknown_field.json",
+			expectedErr: errors.New("json: unknown field \"availableFrom\""),
+		},
+		{
+			desc:        "when fleet has duplicate vehicles",
+			file:        "./testdata/duplicate_id.json",
+			expectedErr: error_utils.ErrFleetVehicle(1, `duplicate id "VAN1"`),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result, err := LoadFleet(test.file)
+			if test.expectedErr != nil {
+				if err == nil || err.Error() != test.expectedErr.Error() {
+					t.Errorf("expected %v, received %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("should not return error, received %v", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %v received %v", test.expected, result)
+			}
+		})
+	}
+}
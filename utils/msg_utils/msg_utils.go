@@ -6,5 +6,14 @@ const (
 	MsgBaseCostPkgCountHeader = "Enter \"base delivery cost\" and \"No of packages\":"
 	MsgPackageDetailsHeader   = "Enter package id, weight, distance and offer code:"
 	MsgVehiclesHeader         = "Enter \"vehicles count\" \"speed\" \"weight capacity\":"
+	MsgVehicleDetailsHeader   = "Enter vehicle id, type, weight capacity, speed and available at (optional):"
 	MsgProgramChoice          = "Do you want compute est time for delivery [yes, no]"
+	MsgOffersReloaded         = "Offers reloaded from %s (%d offers)"
+	MsgOffersReloadFailed     = "Offers reload from %s failed, last valid offers are active: %v"
+	MsgValidOffers            = "Valid configuration"
+	MsgInvalidOffers          = "Invalid configuration"
+	MsgOffersLintClean        = "No issues found"
+	MsgOfferSimulationHeader  = "Offer Code, Current Packages, Current Discount, Current Avg Discount, Proposed Packages, Proposed Discount, Proposed Avg Discount, Discount Difference"
+	MsgOfferSimulationTotal   = "Total (%d packages)"
+	MsgManifestHeader         = "Vehicle, Trip, Departure, Packages, Load, Furthest Distance, Return"
 )
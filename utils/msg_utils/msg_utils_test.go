@@ -23,7 +23,40 @@ func TestMessages(t *testing.T) {
 		t.Error("should not be changed")
 	}
 
+	if MsgVehicleDetailsHeader != "Enter vehicle id, type, weight capacity, speed and available at (optional):" {
+		t.Error("should not be changed")
+	}
+
 	if MsgProgramChoice != "Do you want compute est time for delivery [yes, no]" {
 		t.Error("should not be changed")
 	}
+
+	if MsgOffersReloaded != "Offers reloaded from %s (%d offers)" {
+		t.Error("should not be changed")
+	}
+
+	if MsgOffersReloadFailed != "Offers reload from %s failed, last valid offers are active: %v" {
+		t.Error("should not be changed")
+	}
+
+	if MsgValidOffers != "Valid configuration" {
+		t.Error("should not be changed")
+	}
+
+	if MsgInvalidOffers != "Invalid configuration" {
+		t.Error("should not be changed")
+	}
+
+	if MsgOffersLintClean != "No issues found" {
+		t.Error("should not be changed")
+	}
+	if MsgOfferSimulationHeader != "Offer Code, Current Packages, Current Discount, Current Avg Discount, Proposed Packages, Proposed Discount, Proposed Avg Discount, Discount Difference" {
+		t.Error("should not be changed")
+	}
+	if MsgOfferSimulationTotal != "Total (%d packages)" {
+		t.Error("should not be changed")
+	}
+	if MsgManifestHeader != "Vehicle, Trip, Departure, Packages, Load, Furthest Distance, Return" {
+		t.Error("should not be changed")
+	}
 }
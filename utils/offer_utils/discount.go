@@ -0,0 +1,77 @@
+package offer_utils
+
+import (
+	"fmt"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+// Discount of the offer for the given delivery cost, rounded using the given rounding mode
+//
+//	percentage: delivery cost * discount, raised to min discount and capped at max discount
+//	flat: discount
+//
+// Discount never exceeds the delivery cost
+func DiscountAmount(offer models.Offer, deliveryCost models.Money, rounding models.RoundingMode) models.Money {
+	var amount models.Money
+	switch offer.Type() {
+	case models.DiscountFlat:
+		amount = models.NewMoney(offer.Discount, rounding)
+	default:
+		amount = deliveryCost.MulRatio(offer.Discount, rounding)
+		if offer.MinDiscount != nil {
+			if floor := models.NewMoney(*offer.MinDiscount, rounding); amount < floor {
+				amount = floor
+			}
+		}
+		if offer.MaxDiscount != nil {
+			if limit := models.NewMoney(*offer.MaxDiscount, rounding); amount > limit {
+				amount = limit
+			}
+		}
+	}
+	if amount > deliveryCost {
+		amount = deliveryCost
+	}
+	if amount < 0 {
+		amount = 0
+	}
+	return amount
+}
+
+// Describes what is wrong with the discount of the offer, empty when it is valid
+func discountProblem(offer models.Offer) string {
+	if !models.IsValidDiscountType(offer.Type()) {
+		return fmt.Sprintf("unknown discount type %q, expected one of percentage, flat", offer.DiscountType)
+	}
+	if offer.Type() == models.DiscountFlat {
+		if offer.Discount < 0 {
+			return fmt.Sprintf("flat discount should not be negative, received %v", offer.Discount)
+		}
+		if offer.MinDiscount != nil || offer.MaxDiscount != nil {
+			return "minDiscount and maxDiscount are supported by percentage discounts only"
+		}
+		return ""
+	}
+	if offer.Discount < MinDiscountRatio || offer.Discount > MaxDiscountRatio {
+		return fmt.Sprintf("discount should be between %d and %d, received %v", MinDiscountRatio, MaxDiscountRatio, offer.Discount)
+	}
+	if offer.MinDiscount != nil && *offer.MinDiscount < 0 {
+		return fmt.Sprintf("minDiscount should not be negative, received %v", *offer.MinDiscount)
+	}
+	if offer.MaxDiscount != nil && *offer.MaxDiscount < 0 {
+		return fmt.Sprintf("maxDiscount should not be negative, received %v", *offer.MaxDiscount)
+	}
+	if offer.MinDiscount != nil && offer.MaxDiscount != nil && *offer.MinDiscount > *offer.MaxDiscount {
+		return "minDiscount should not exceed maxDiscount"
+	}
+	return ""
+}
+
+func validateDiscount(offer models.Offer) error {
+	if reason := discountProblem(offer); reason != "" {
+		return error_utils.ErrOfferDiscount(offer.Code, reason)
+	}
+	return nil
+}
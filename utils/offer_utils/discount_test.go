@@ -0,0 +1,139 @@
+package offer_utils
+
+import (
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+func amount(value float64) *float64 {
+	return &value
+}
+
+func TestDiscountAmount(t *testing.T) {
+	tt := []struct {
+		desc         string
+		offer        models.Offer
+		deliveryCost models.Money
+		expected     models.Money
+	}{
+		{
+			desc:         "percentage",
+			offer:        models.Offer{Discount: 0.1},
+			deliveryCost: 70000,
+			expected:     7000,
+		},
+		{
+			desc:         "flat",
+			offer:        models.Offer{Discount: 50, DiscountType: models.DiscountFlat},
+			deliveryCost: 70000,
+			expected:     5000,
+		},
+		{
+			desc:         "flat more than delivery cost",
+			offer:        models.Offer{Discount: 50, DiscountType: models.DiscountFlat},
+			deliveryCost: 3000,
+			expected:     3000,
+		},
+		{
+			desc:         "percentage capped at max discount",
+			offer:        models.Offer{Discount: 0.1, MaxDiscount: amount(100)},
+			deliveryCost: 200000,
+			expected:     10000,
+		},
+		{
+			desc:         "percentage within max discount",
+			offer:        models.Offer{Discount: 0.1, MaxDiscount: amount(100)},
+			deliveryCost: 50000,
+			expected:     5000,
+		},
+		{
+			desc:         "percentage raised to min discount",
+			offer:        models.Offer{Discount: 0.1, MinDiscount: amount(20)},
+			deliveryCost: 10000,
+			expected:     2000,
+		},
+		{
+			desc:         "min discount more than delivery cost",
+			offer:        models.Offer{Discount: 0.1, MinDiscount: amount(20)},
+			deliveryCost: 1500,
+			expected:     1500,
+		},
+		{
+			desc:         "rounds flat discount",
+			offer:        models.Offer{Discount: 10.005, DiscountType: models.DiscountFlat},
+			deliveryCost: 70000,
+			expected:     1001,
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			if result := DiscountAmount(test.offer, test.deliveryCost, models.RoundHalfUp); result != test.expected {
+				t.Errorf("expected %v, received %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateOffersDiscount(t *testing.T) {
+	conditions := []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 10}}
+	tt := []struct {
+		desc     string
+		offer    models.Offer
+		expected error
+	}{
+		{
+			desc:  "with capped percentage",
+			offer: models.Offer{Code: "A", Conditions: conditions, Discount: 0.1, MinDiscount: amount(20), MaxDiscount: amount(100)},
+		},
+		{
+			desc:  "with flat amount",
+			offer: models.Offer{Code: "A", Conditions: conditions, Discount: 50, DiscountType: models.DiscountFlat},
+		},
+		{
+			desc:     "with unknown type",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Discount: 1, DiscountType: "bogo"},
+			expected: error_utils.ErrOfferDiscount("A", `unknown discount type "bogo", expected one of percentage, flat`),
+		},
+		{
+			desc:     "with percentage out of range",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Discount: 10},
+			expected: error_utils.ErrOfferDiscount("A", "discount should be between 0 and 1, received 10"),
+		},
+		{
+			desc:     "with negative flat amount",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Discount: -5, DiscountType: models.DiscountFlat},
+			expected: error_utils.ErrOfferDiscount("A", "flat discount should not be negative, received -5"),
+		},
+		{
+			desc:     "with cap on flat amount",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Discount: 5, DiscountType: models.DiscountFlat, MaxDiscount: amount(100)},
+			expected: error_utils.ErrOfferDiscount("A", "minDiscount and maxDiscount are supported by percentage discounts only"),
+		},
+		{
+			desc:     "with negative cap",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Discount: 0.1, MaxDiscount: amount(-1)},
+			expected: error_utils.ErrOfferDiscount("A", "maxDiscount should not be negative, received -1"),
+		},
+		{
+			desc:     "with floor above cap",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Discount: 0.1, MinDiscount: amount(200), MaxDiscount: amount(100)},
+			expected: error_utils.ErrOfferDiscount("A", "minDiscount should not exceed maxDiscount"),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffers([]models.Offer{test.offer})
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
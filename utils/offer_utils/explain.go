@@ -0,0 +1,57 @@
+package offer_utils
+
+import (
+	"fmt"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+// Outcome of every condition of the offer for the package, flat conditions followed by the conditions of the rules
+// Pass of a rule condition is its own outcome, groups (any, not) decide how it contributes to the offer
+func ExplainConditions(offer models.Offer, fact models.Fact) []models.ConditionResult {
+	results := []models.ConditionResult{}
+	for _, condition := range offer.Conditions {
+		results = append(results, explainCondition("", condition, fact))
+	}
+	if offer.Rules != nil {
+		results = explainRule(results, "rules", *offer.Rules, fact)
+	}
+	return results
+}
+
+func explainRule(results []models.ConditionResult, path string, node models.ConditionNode, fact models.Fact) []models.ConditionResult {
+	switch {
+	case node.IsLeaf():
+		return append(results, explainCondition(path, *node.Condition, fact))
+	case node.Not != nil:
+		return explainRule(results, path+".not", *node.Not, fact)
+	case len(node.Any) > 0:
+		for i, child := range node.Any {
+			results = explainRule(results, fmt.Sprintf("%s.any[%d]", path, i), child, fact)
+		}
+		return results
+	}
+	for i, child := range node.All {
+		results = explainRule(results, fmt.Sprintf("%s.all[%d]", path, i), child, fact)
+	}
+	return results
+}
+
+func explainCondition(rule string, condition models.Condition, fact models.Fact) models.ConditionResult {
+	result := models.ConditionResult{
+		Rule:     rule,
+		Fact:     condition.Fact,
+		Operator: condition.Operator,
+		Expected: condition.Expected(),
+		Actual:   "unknown fact",
+		Pass:     isConditionSatisfied(condition, fact),
+	}
+	if definition, ok := LookupFact(condition.Fact); ok {
+		if definition.Kind == FactKindText {
+			result.Actual = fmt.Sprintf("%q", definition.Text(fact))
+		} else {
+			result.Actual = fmt.Sprintf("%v", definition.Number(fact))
+		}
+	}
+	return result
+}
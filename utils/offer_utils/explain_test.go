@@ -0,0 +1,59 @@
+package offer_utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+func TestExplainConditions(t *testing.T) {
+	fact := models.Fact{Weight: 10, Distance: 120, Code: "OFR003"}
+	tt := []struct {
+		desc     string
+		offer    models.Offer
+		expected []models.ConditionResult
+	}{
+		{
+			desc: "flat conditions",
+			offer: models.Offer{Conditions: []models.Condition{
+				{Fact: models.FactDistance, Operator: models.LessThan, Value: 200},
+				{Fact: models.FactWeight, Operator: models.Between, Values: []float64{70, 200}},
+				{Fact: models.FactCodePrefix, Operator: models.Equal, Text: "OFR"},
+			}},
+			expected: []models.ConditionResult{
+				{Fact: "distance", Operator: "lessThan", Expected: "200", Actual: "120", Pass: true},
+				{Fact: "weight", Operator: "between", Expected: "70 and 200", Actual: "10", Pass: false},
+				{Fact: "codePrefix", Operator: "equal", Expected: `"OFR"`, Actual: `"OFR"`, Pass: true},
+			},
+		},
+		{
+			desc: "rules",
+			offer: models.Offer{
+				Conditions: []models.Condition{{Fact: models.FactWeight, Operator: models.In, Values: []float64{10, 20}}},
+				Rules: &models.ConditionNode{Any: []models.ConditionNode{
+					{Condition: &models.Condition{Fact: models.FactDistance, Operator: models.GreaterThan, Value: 150}},
+					{Not: &models.ConditionNode{Condition: &models.Condition{Fact: models.FactWeight, Operator: models.GreaterThanOrEqual, Value: 5}}},
+				}},
+			},
+			expected: []models.ConditionResult{
+				{Fact: "weight", Operator: "in", Expected: "[10 20]", Actual: "10", Pass: true},
+				{Rule: "rules.any[0]", Fact: "distance", Operator: "greaterThan", Expected: "150", Actual: "120", Pass: false},
+				{Rule: "rules.any[1].not", Fact: "weight", Operator: "greaterThanOrEqual", Expected: "5", Actual: "10", Pass: true},
+			},
+		},
+		{
+			desc:     "unknown fact",
+			offer:    models.Offer{Conditions: []models.Condition{{Fact: "volume", Operator: models.LessThan, Value: 1}}},
+			expected: []models.ConditionResult{{Fact: "volume", Operator: "lessThan", Expected: "1", Actual: "unknown fact", Pass: false}},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := ExplainConditions(tc.offer, fact)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("ExplainConditions() = %+v, want %+v", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package offer_utils
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+type FactKind string
+
+const (
+	FactKindNumber FactKind = "number"
+	FactKindText   FactKind = "text"
+)
+
+// Resolves the value of a fact (for a package), Number is used by number facts and Text by text facts
+type FactDefinition struct {
+	Kind   FactKind
+	Number func(models.Fact) float64
+	Text   func(models.Fact) string
+}
+
+var (
+	factsMu sync.RWMutex
+	facts   = map[string]FactDefinition{
+		models.FactWeight:           numberFact(func(f models.Fact) float64 { return f.Weight }),
+		models.FactDistance:         numberFact(func(f models.Fact) float64 { return f.Distance }),
+		models.FactBaseDeliveryCost: numberFact(func(f models.Fact) float64 { return f.BaseDeliveryCost }),
+		models.FactDeliveryCost:     numberFact(func(f models.Fact) float64 { return f.DeliveryCost }),
+		models.FactPackageCount:     numberFact(func(f models.Fact) float64 { return float64(f.PackageCount) }),
+		models.FactEstDeliveryTime:  numberFact(func(f models.Fact) float64 { return f.EstDeliveryTime }),
+		models.FactCodePrefix:       textFact(func(f models.Fact) string { return codePrefix(f.Code) }),
+	}
+)
+
+func numberFact(fn func(models.Fact) float64) FactDefinition {
+	return FactDefinition{Kind: FactKindNumber, Number: fn}
+}
+
+func textFact(fn func(models.Fact) string) FactDefinition {
+	return FactDefinition{Kind: FactKindText, Text: fn}
+}
+
+// Leading letters of the offer code (OFR of OFR001)
+func codePrefix(code models.OfferCode) string {
+	value := string(code)
+	end := strings.IndexFunc(value, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end == -1 {
+		return value
+	}
+	return value[:end]
+}
+
+// Makes a fact available to offer conditions, so that offers can target it
+// Facts are registered once (usually from init), an existing fact can not be replaced
+func RegisterFact(name string, definition FactDefinition) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return error_utils.ErrMissingInput
+	}
+	if (definition.Kind == FactKindNumber && definition.Number == nil) || (definition.Kind == FactKindText && definition.Text == nil) || (definition.Kind != FactKindNumber && definition.Kind != FactKindText) {
+		return error_utils.ErrFactDefinition(name)
+	}
+
+	factsMu.Lock()
+	defer factsMu.Unlock()
+	if _, ok := facts[name]; ok {
+		return error_utils.ErrFactRegistered(name)
+	}
+	facts[name] = definition
+	return nil
+}
+
+func LookupFact(name string) (FactDefinition, bool) {
+	factsMu.RLock()
+	defer factsMu.RUnlock()
+	definition, ok := facts[name]
+	return definition, ok
+}
+
+// Names of all registered facts (sorted)
+func RegisteredFacts() []string {
+	factsMu.RLock()
+	defer factsMu.RUnlock()
+	names := make([]string, 0, len(facts))
+	for name := range facts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
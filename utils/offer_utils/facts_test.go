@@ -0,0 +1,148 @@
+package offer_utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+func TestRegisteredFacts(t *testing.T) {
+	result := RegisteredFacts()
+	for _, name := range []string{"baseDeliveryCost", "codePrefix", "deliveryCost", "distance", "estDeliveryTime", "packageCount", "weight"} {
+		if _, ok := LookupFact(name); !ok {
+			t.Errorf("%s should be a registered fact, registered %v", name, result)
+		}
+	}
+	if _, ok := LookupFact("volume"); ok {
+		t.Error("volume should not be a registered fact")
+	}
+}
+
+// Drops a fact registered by a test, so that the test can be run again (-count)
+func unregisterFact(name string) {
+	factsMu.Lock()
+	defer factsMu.Unlock()
+	delete(facts, name)
+}
+
+func TestRegisterFact(t *testing.T) {
+	t.Cleanup(func() { unregisterFact("weightPerKm") })
+	volumetric := FactDefinition{Kind: FactKindNumber, Number: func(f models.Fact) float64 { return f.Weight / f.Distance }}
+
+	tt := []struct {
+		desc       string
+		name       string
+		definition FactDefinition
+		expected   error
+	}{
+		{desc: "new number fact", name: "weightPerKm", definition: volumetric},
+		{desc: "existing fact", name: "weight", definition: volumetric, expected: error_utils.ErrFactRegistered("weight")},
+		{desc: "without name", name: " ", definition: volumetric, expected: error_utils.ErrMissingInput},
+		{desc: "without resolver", name: "volume", definition: FactDefinition{Kind: FactKindNumber}, expected: error_utils.ErrFactDefinition("volume")},
+		{desc: "with text kind and number resolver", name: "volume", definition: FactDefinition{Kind: FactKindText, Number: volumetric.Number}, expected: error_utils.ErrFactDefinition("volume")},
+		{desc: "with unknown kind", name: "volume", definition: FactDefinition{Kind: "date", Number: volumetric.Number}, expected: error_utils.ErrFactDefinition("volume")},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := RegisterFact(test.name, test.definition)
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+
+	// registered fact can be used by offers
+	offer := models.Offer{Code: "A", Conditions: []models.Condition{{Fact: "weightPerKm", Operator: models.GreaterThanOrEqual, Value: 2}}}
+	if err := ValidateOffers([]models.Offer{offer}); err != nil {
+		t.Errorf("should not return error, received %v", err)
+	}
+	if !IsApplicable(offer, models.Fact{Weight: 20, Distance: 10}) {
+		t.Error("offer on registered fact should be applicable")
+	}
+}
+
+func TestBuiltInFacts(t *testing.T) {
+	fact := models.Fact{
+		Weight:           10,
+		Distance:         20,
+		BaseDeliveryCost: 100,
+		DeliveryCost:     300,
+		PackageCount:     3,
+		EstDeliveryTime:  1.5,
+		Code:             "OFR001",
+	}
+
+	tt := []struct {
+		desc      string
+		condition models.Condition
+		expected  bool
+	}{
+		{desc: "weight", condition: models.Condition{Fact: "weight", Operator: models.Equal, Value: 10}, expected: true},
+		{desc: "distance", condition: models.Condition{Fact: "distance", Operator: models.Equal, Value: 20}, expected: true},
+		{desc: "base delivery cost", condition: models.Condition{Fact: "baseDeliveryCost", Operator: models.Equal, Value: 100}, expected: true},
+		{desc: "delivery cost", condition: models.Condition{Fact: "deliveryCost", Operator: models.GreaterThan, Value: 250}, expected: true},
+		{desc: "package count", condition: models.Condition{Fact: "packageCount", Operator: models.In, Values: []float64{2, 3}}, expected: true},
+		{desc: "est delivery time", condition: models.Condition{Fact: "estDeliveryTime", Operator: models.LessThan, Value: 1}, expected: false},
+		{desc: "code prefix", condition: models.Condition{Fact: "codePrefix", Operator: models.Equal, Text: "OFR"}, expected: true},
+		{desc: "code prefix (not equal)", condition: models.Condition{Fact: "codePrefix", Operator: models.NotEqual, Text: "OFR"}, expected: false},
+		{desc: "code prefix (unsupported operator)", condition: models.Condition{Fact: "codePrefix", Operator: models.LessThan, Text: "OFR"}, expected: false},
+		{desc: "unknown fact", condition: models.Condition{Fact: "volume", Operator: models.Equal, Value: 0}, expected: false},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result := isConditionSatisfied(test.condition, fact)
+			if test.expected != result {
+				t.Errorf("expected %t received %t", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestCodePrefix(t *testing.T) {
+	tt := map[models.OfferCode]string{
+		"OFR001": "OFR",
+		"SUMMER": "SUMMER",
+		"001":    "",
+		"":       "",
+	}
+	for code, expected := range tt {
+		if result := codePrefix(code); result != expected {
+			t.Errorf("expected %q received %q", expected, result)
+		}
+	}
+}
+
+func TestValidateFacts(t *testing.T) {
+	tt := []struct {
+		desc      string
+		condition models.Condition
+		expected  error
+	}{
+		{desc: "unknown fact", condition: models.Condition{Fact: "volume", Operator: models.Equal, Value: 10}, expected: error_utils.ErrOfferUnknownFact("A", "volume")},
+		{desc: "text fact", condition: models.Condition{Fact: "codePrefix", Operator: models.Equal, Text: "OFR"}},
+		{desc: "text fact with number operator", condition: models.Condition{Fact: "codePrefix", Operator: models.LessThan, Text: "OFR"}, expected: error_utils.ErrOfferConditionValues("A", models.LessThan, "is not supported by text fact codePrefix")},
+		{desc: "number fact with text", condition: models.Condition{Fact: "weight", Operator: models.Equal, Text: "OFR"}, expected: error_utils.ErrOfferConditionValues("A", models.Equal, "does not accept text for number fact weight")},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffers([]models.Offer{{Code: "A", Conditions: []models.Condition{test.condition}}})
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(err, test.expected) {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
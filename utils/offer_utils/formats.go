@@ -0,0 +1,121 @@
+package offer_utils
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+// Formats in which offers are defined, all of them decode to the same offers
+type OfferFormat string
+
+const (
+	FormatJSON OfferFormat = "json" // array of offers
+	FormatYAML OfferFormat = "yaml" // sequence of offers
+	FormatTOML OfferFormat = "toml" // array of tables named offers ([[offers]])
+)
+
+// Format of an offers file as per its extension (.yaml, .yml, .toml), json otherwise
+func FormatOf(filename string) OfferFormat {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	}
+	return FormatJSON
+}
+
+// Validates offers document of the given format against the offers schema
+// Returns SchemaErrors listing every violation (with line numbers of the source), or a syntax error
+func ValidateOffersSchemaFormat(content []byte, format OfferFormat) error {
+	root, err := parseOffers(content, format)
+	if err != nil {
+		return err
+	}
+	return validateOffersNode(root)
+}
+
+func parseOffers(content []byte, format OfferFormat) (*schemaNode, error) {
+	switch format {
+	case FormatYAML:
+		return parseYAML(content)
+	case FormatTOML:
+		return parseTOML(content)
+	}
+	return parseJSON(content)
+}
+
+// Decodes offers of the given format, validated against the schema and the offer engine
+func DecodeOffersFormat(content []byte, format OfferFormat) ([]models.Offer, error) {
+	if format == FormatJSON {
+		return DecodeOffers(content)
+	}
+	root, err := parseOffers(content, format)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateOffersNode(root); err != nil {
+		return nil, err
+	}
+
+	// offers are decoded the same way as json, once the document is known to be valid
+	offers, err := decodeOffersNode(root)
+	if err != nil {
+		return nil, err
+	}
+	if err = ValidateOffers(offers); err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+// Decodes offers of the given format without validating them (ex: to lint them), only syntax and type errors are reported
+func DecodeOffersFormatLenient(content []byte, format OfferFormat) ([]models.Offer, error) {
+	root, err := parseOffers(content, format)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOffersNode(root)
+}
+
+func decodeOffersNode(root *schemaNode) ([]models.Offer, error) {
+	content, err := json.Marshal(root.value())
+	if err != nil {
+		return nil, err
+	}
+	var offers []models.Offer
+	if err = json.Unmarshal(content, &offers); err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+// Plain value of the node, as decoded by encoding/json
+func (n *schemaNode) value() interface{} {
+	switch n.kind {
+	case kindObject:
+		object := make(map[string]interface{}, len(n.members))
+		for _, m := range n.members {
+			object[m.key] = m.value.value()
+		}
+		return object
+	case kindArray:
+		items := make([]interface{}, len(n.items))
+		for i, item := range n.items {
+			items[i] = item.value()
+		}
+		return items
+	case kindNumber:
+		// integers (ex: priority) are kept as integers
+		return json.Number(strconv.FormatFloat(n.number, 'f', -1, 64))
+	case kindString:
+		return n.text
+	case kindBool:
+		return n.boolean
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package offer_utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatOf(t *testing.T) {
+	tt := []struct {
+		filename string
+		expected OfferFormat
+	}{
+		{filename: "offers.json", expected: FormatJSON},
+		{filename: "config/offers.yaml", expected: FormatYAML},
+		{filename: "offers.YML", expected: FormatYAML},
+		{filename: "offers.toml", expected: FormatTOML},
+		{filename: "offers", expected: FormatJSON},
+	}
+	for _, test := range tt {
+		t.Run(test.filename, func(t *testing.T) {
+			if result := FormatOf(test.filename); result != test.expected {
+				t.Errorf("expected %s received %s", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestLoadOffersFormats(t *testing.T) {
+	expected, err := LoadOffers("./testdata/formats.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expected) != 2 {
+		t.Fatalf("expected 2 offers received %d", len(expected))
+	}
+
+	for _, filename := range []string{"./testdata/formats.yaml", "./testdata/formats.toml"} {
+		t.Run(filename, func(t *testing.T) {
+			result, err := LoadOffers(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("expected %+v received %+v", expected, result)
+			}
+		})
+	}
+}
+
+func TestLoadOffersLenient(t *testing.T) {
+	for _, filename := range []string{"./testdata/formats.json", "./testdata/formats.yaml", "./testdata/formats.toml"} {
+		expected, _ := LoadOffers(filename)
+		if result, err := LoadOffersLenient(filename); err != nil || !reflect.DeepEqual(result, expected) {
+			t.Errorf("%s: expected %+v received %+v %v", filename, expected, result, err)
+		}
+	}
+
+	// invalid offers are loaded as they are
+	if _, err := LoadOffers("./testdata/lint_invalid.json"); err == nil {
+		t.Error("Should throw error")
+	}
+	offers, err := LoadOffersLenient("./testdata/lint_invalid.json")
+	if err != nil || len(offers) != 2 || offers[0].Discount != 1.5 {
+		t.Errorf("expected invalid offers received %+v %v", offers, err)
+	}
+	if _, err := LoadOffersLenient("./testdata/missing.json"); err == nil {
+		t.Error("Should throw error")
+	}
+}
+
+func TestValidateOffersSchemaFormat(t *testing.T) {
+	tt := []struct {
+		desc     string
+		format   OfferFormat
+		content  string
+		expected []string
+	}{
+		{
+			desc:    "yaml with valid offers",
+			format:  FormatYAML,
+			content: "- code: OFR001\n  discount: 0.1\n  conditions:\n    - {fact: distance, operator: lessThan, value: 200}\n",
+		},
+		{
+			desc:    "yaml with schema violations",
+			format:  FormatYAML,
+			content: "- code: OFR001\n  discount: 1.5\n  autoApply: no\n  conditions:\n    - fact: volume\n      operator: lessThan\n      value: 200\n",
+			expected: []string{
+				"$[0].discount (line 2, column 13): should be between 0 and 1, received 1.5",
+				unknownFactError(5, 13),
+				"$[0].autoApply (line 3, column 14): should be boolean, received string",
+			},
+		},
+		{
+			desc:     "yaml with mapping instead of offers",
+			format:   FormatYAML,
+			content:  "code: OFR001\n",
+			expected: []string{"$ (line 1, column 1): should be array, received object"},
+		},
+		{
+			desc:     "yaml without offers",
+			format:   FormatYAML,
+			content:  "",
+			expected: []string{"$ (line 1, column 1): should be array, received null"},
+		},
+		{
+			desc:     "invalid yaml",
+			format:   FormatYAML,
+			content:  "- code: OFR001\n  discount: 0.1\n   conditions: []\n",
+			expected: []string{"$ (line 3): mapping values are not allowed in this context"},
+		},
+		{
+			desc:     "yaml with several documents",
+			format:   FormatYAML,
+			content:  "- code: OFR001\n---\n- code: OFR002\n",
+			expected: []string{"$ (line 2, column 1): unexpected content after offers"},
+		},
+		{
+			desc:    "toml with valid offers",
+			format:  FormatTOML,
+			content: "[[offers]]\ncode = \"OFR001\"\ndiscount = 0.1\nconditions = [{ fact = \"distance\", operator = \"lessThan\", value = 200 }]\n",
+		},
+		{
+			desc:    "toml with schema violations",
+			format:  FormatTOML,
+			content: "[[offers]]\ncode = \"OFR001\"\ndiscount = 1.5\nstacking = \"all\"\n\n[[offers.conditions]]\nfact = \"volume\"\noperator = \"lessThan\"\nvalue = 200\n",
+			expected: []string{
+				"$[0].discount (line 3, column 12): should be between 0 and 1, received 1.5",
+				unknownFactError(7, 8),
+				`$[0].stacking (line 4, column 12): unknown stacking "all", expected one of exclusive, stackable, bestOf`,
+			},
+		},
+		{
+			desc:     "toml with duplicate property",
+			format:   FormatTOML,
+			content:  "[[offers]]\ncode = \"OFR001\"\ncode = \"OFR002\"\ndiscount = 0.1\nconditions = [{ fact = \"distance\", operator = \"lessThan\", value = 200 }]\n",
+			expected: []string{`$[0].code (line 3, column 1): duplicate property "code"`},
+		},
+		{
+			desc:     "toml without offers",
+			format:   FormatTOML,
+			content:  "[[offer]]\ncode = \"OFR001\"\n",
+			expected: []string{`$.offer (line 1, column 3): unknown property "offer"`},
+		},
+		{
+			desc:     "invalid toml",
+			format:   FormatTOML,
+			content:  "[[offers]]\ncode = \"OFR001\ndiscount = 0.1\n",
+			expected: []string{"$ (line 2, column 15): basic strings cannot have new lines"},
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffersSchemaFormat([]byte(test.content), test.format)
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			expected := strings.Join(test.expected, "\n")
+			if err == nil || err.Error() != expected {
+				t.Errorf("expected\n%v\nreceived\n%v", expected, err)
+			}
+		})
+	}
+}
+
+func unknownFactError(line int, column int) string {
+	return (SchemaError{Path: "$[0].conditions[0].fact", Line: line, Column: column, Message: `unknown fact "volume", expected one of ` + strings.Join(RegisteredFacts(), ", ")}).Error()
+}
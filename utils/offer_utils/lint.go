@@ -0,0 +1,442 @@
+package offer_utils
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"   // offer is broken, ex: never applies
+	LintWarning LintSeverity = "warning" // offer works, but probably not as intended
+)
+
+// Checks performed by the offers linter
+const (
+	LintDuplicateCode = "duplicate-code"
+	LintDiscountRange = "discount-range"
+	LintUnsatisfiable = "unsatisfiable"
+	LintRedundant     = "redundant-condition"
+	LintOverlap       = "overlap"
+)
+
+// Facts whose eligible regions are compared between offers
+var overlapFacts = []string{models.FactWeight, models.FactDistance}
+
+type LintIssue struct {
+	Code     models.OfferCode
+	Severity LintSeverity
+	Check    string
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s %s %s: %s", i.Code, i.Severity, i.Check, i.Message)
+}
+
+func HasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports duplicate codes, invalid discounts (ex: percentage outside 0-1), unsatisfiable and redundant conditions,
+// and offers whose eligible weight/distance regions overlap.
+// Unsatisfiable and redundant conditions are checked among the conditions which should all be satisfied (flat conditions and all groups of rules),
+// and within every alternative (any) of the rules. Conditions under not are not checked. Overlaps take rules into account too.
+func LintOffers(offers []models.Offer) []LintIssue {
+	issues := []LintIssue{}
+	counts := make(map[models.OfferCode]int)
+	for _, offer := range offers {
+		counts[offer.Code]++
+	}
+
+	satisfiable := make([]bool, len(offers))
+	for i, offer := range offers {
+		if counts[offer.Code] > 1 {
+			issues = append(issues, LintIssue{offer.Code, LintError, LintDuplicateCode, fmt.Sprintf("code is defined %d times, only the last one is used", counts[offer.Code])})
+			counts[offer.Code] = 0 // reported once
+		}
+		if reason := discountProblem(offer); reason != "" {
+			issues = append(issues, LintIssue{offer.Code, LintError, LintDiscountRange, reason})
+		}
+
+		satisfiable[i] = true
+		for _, group := range conditionGroups(offer) {
+			for _, fact := range numberFacts(group.conditions) {
+				conditions := conditionsOf(group.conditions, fact)
+				if regionOf(conditions).isEmpty() {
+					// an alternative (any) which never applies does not keep the offer from applying
+					if group.required {
+						satisfiable[i] = false
+					}
+					issues = append(issues, LintIssue{offer.Code, LintError, LintUnsatisfiable, fmt.Sprintf("%s conditions%s can never be satisfied together", fact, group.where())})
+					continue
+				}
+				issues = append(issues, redundantConditions(offer.Code, conditions, group.where())...)
+			}
+		}
+	}
+
+	for i := range offers {
+		for j := i + 1; j < len(offers); j++ {
+			a, b := offers[i], offers[j]
+			if a.Code == b.Code || !satisfiable[i] || !satisfiable[j] {
+				continue
+			}
+			if overlaps(a, b) {
+				issues = append(issues, LintIssue{a.Code, LintWarning, LintOverlap, fmt.Sprintf("weight/distance region overlaps with %s", b.Code)})
+			}
+		}
+	}
+	return issues
+}
+
+// Conditions which should all be satisfied together, either the conditions of an offer (flat ones and the ones of its rules) or an alternative of its rules
+type conditionGroup struct {
+	path       string // of the alternative in the rules (ex: rules.any[1]), empty for the conditions of the offer
+	conditions []models.Condition
+	required   bool // the offer applies only when the group is satisfied (not an alternative of an any group)
+}
+
+func (g conditionGroup) where() string {
+	if g.path == "" {
+		return ""
+	}
+	return " of " + g.path
+}
+
+// Flat conditions of the offer along with the conditions of its rules which should all be satisfied, followed by every alternative (any) of the rules
+func conditionGroups(offer models.Offer) []conditionGroup {
+	groups := []conditionGroup{{conditions: allConditions(offer.ConditionTree()), required: true}}
+	switch rules := offer.Rules; {
+	case rules == nil:
+	case len(rules.Any) > 0:
+		groups = appendGroups(groups, "rules", *rules, true)
+	default:
+		groups = appendAlternatives(groups, "rules", *rules, true)
+	}
+	return groups
+}
+
+// Groups of the node, each alternative of an any group is a group of its own
+func appendGroups(groups []conditionGroup, path string, node models.ConditionNode, required bool) []conditionGroup {
+	switch {
+	case node.IsLeaf(), node.Not != nil:
+		return groups
+	case len(node.Any) > 0:
+		for i, child := range node.Any {
+			groups = appendGroups(groups, fmt.Sprintf("%s.any[%d]", path, i), child, false)
+		}
+		return groups
+	}
+	groups = append(groups, conditionGroup{path: path, conditions: allConditions(node), required: required})
+	return appendAlternatives(groups, path, node, required)
+}
+
+// Groups of the any groups within the all group (nested all groups included)
+func appendAlternatives(groups []conditionGroup, path string, node models.ConditionNode, required bool) []conditionGroup {
+	for i, child := range node.All {
+		childPath := fmt.Sprintf("%s.all[%d]", path, i)
+		switch {
+		case len(child.Any) > 0:
+			groups = appendGroups(groups, childPath, child, required)
+		case len(child.All) > 0:
+			groups = appendAlternatives(groups, childPath, child, required)
+		}
+	}
+	return groups
+}
+
+// Conditions of the all group, along with the ones of nested all groups
+func allConditions(node models.ConditionNode) []models.Condition {
+	conditions := []models.Condition{}
+	for _, child := range node.All {
+		switch {
+		case child.IsLeaf():
+			conditions = append(conditions, *child.Condition)
+		case len(child.All) > 0:
+			conditions = append(conditions, allConditions(child)...)
+		}
+	}
+	return conditions
+}
+
+// Conditions which are implied by the other conditions on the same fact
+func redundantConditions(code models.OfferCode, conditions []models.Condition, where string) []LintIssue {
+	issues := []LintIssue{}
+	redundant := make([]bool, len(conditions))
+	for i, condition := range conditions {
+		others := []models.Condition{}
+		for j, other := range conditions {
+			if j != i && !redundant[j] {
+				others = append(others, other)
+			}
+		}
+		if len(others) > 0 && regionOf(others).within(condition) {
+			redundant[i] = true
+			issues = append(issues, LintIssue{code, LintWarning, LintRedundant, fmt.Sprintf("%q is implied by the other %s conditions%s", condition, condition.Fact, where)})
+		}
+	}
+	return issues
+}
+
+// A package can be eligible for both offers, when any alternative of an offer overlaps any alternative of the other one
+func overlaps(a models.Offer, b models.Offer) bool {
+	others := alternatives(b.ConditionTree(), false)
+	for _, x := range alternatives(a.ConditionTree(), false) {
+		for _, y := range others {
+			if regionsOverlap(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func regionsOverlap(a []models.Condition, b []models.Condition) bool {
+	for _, fact := range overlapFacts {
+		combined := append(conditionsOf(a, fact), conditionsOf(b, fact)...)
+		if regionOf(combined).isEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Alternatives of a condition tree (satisfied when any of them is), each one the weight/distance conditions satisfied together.
+// Conditions on other facts are left out, so the alternatives cover at least every eligible package
+func alternatives(node models.ConditionNode, negated bool) [][]models.Condition {
+	switch {
+	case node.IsLeaf():
+		if !contains(overlapFacts, node.Fact) {
+			return [][]models.Condition{{}}
+		}
+		if negated {
+			return negation(*node.Condition)
+		}
+		return [][]models.Condition{{*node.Condition}}
+	case node.Not != nil:
+		return alternatives(*node.Not, !negated)
+	}
+
+	children, any := node.All, false
+	if len(node.Any) > 0 {
+		children, any = node.Any, true
+	}
+	// not all is any of not, not any is all of not
+	if any != negated {
+		result := [][]models.Condition{}
+		for _, child := range children {
+			result = append(result, alternatives(child, negated)...)
+		}
+		return result
+	}
+	result := [][]models.Condition{{}}
+	for _, child := range children {
+		combined := [][]models.Condition{}
+		for _, x := range result {
+			for _, y := range alternatives(child, negated) {
+				combined = append(combined, append(append([]models.Condition{}, x...), y...))
+			}
+		}
+		result = combined
+	}
+	return result
+}
+
+// Alternatives satisfied when the condition is not, unconstrained when the negation is not expressed as conditions
+func negation(c models.Condition) [][]models.Condition {
+	negated := func(operator string, value float64) models.Condition {
+		return models.Condition{Fact: c.Fact, Operator: operator, Value: value}
+	}
+	switch c.Operator {
+	case models.LessThan:
+		return [][]models.Condition{{negated(models.GreaterThanOrEqual, c.Value)}}
+	case models.LessThanOrEqual:
+		return [][]models.Condition{{negated(models.GreaterThan, c.Value)}}
+	case models.GreaterThan:
+		return [][]models.Condition{{negated(models.LessThanOrEqual, c.Value)}}
+	case models.GreaterThanOrEqual:
+		return [][]models.Condition{{negated(models.LessThan, c.Value)}}
+	case models.Equal:
+		return [][]models.Condition{{negated(models.NotEqual, c.Value)}}
+	case models.NotEqual:
+		return [][]models.Condition{{negated(models.Equal, c.Value)}}
+	case models.In:
+		excluded := []models.Condition{}
+		for _, value := range c.Values {
+			excluded = append(excluded, negated(models.NotEqual, value))
+		}
+		return [][]models.Condition{excluded}
+	case models.Between:
+		if len(c.Values) == 2 {
+			if c.Exclusive {
+				return [][]models.Condition{{negated(models.LessThanOrEqual, c.Values[0])}, {negated(models.GreaterThanOrEqual, c.Values[1])}}
+			}
+			return [][]models.Condition{{negated(models.LessThan, c.Values[0])}, {negated(models.GreaterThan, c.Values[1])}}
+		}
+	}
+	return [][]models.Condition{{}}
+}
+
+// Facts (number kind) referenced by the conditions, in order of appearance
+func numberFacts(conditions []models.Condition) []string {
+	facts := []string{}
+	for _, condition := range conditions {
+		definition, ok := LookupFact(condition.Fact)
+		if ok && definition.Kind == FactKindNumber && !contains(facts, condition.Fact) {
+			facts = append(facts, condition.Fact)
+		}
+	}
+	return facts
+}
+
+func conditionsOf(conditions []models.Condition, fact string) []models.Condition {
+	result := []models.Condition{}
+	for _, condition := range conditions {
+		if condition.Fact == fact {
+			result = append(result, condition)
+		}
+	}
+	return result
+}
+
+// Values of a number fact satisfying a set of conditions (all of them)
+type region struct {
+	lo, hi         float64
+	loOpen, hiOpen bool
+	points         []float64 // only these values are allowed (equal, in), nil allows any value within bounds
+	excluded       []float64 // notEqual
+}
+
+func regionOf(conditions []models.Condition) region {
+	r := region{lo: math.Inf(-1), hi: math.Inf(1), loOpen: true, hiOpen: true}
+	for _, condition := range conditions {
+		r = r.restrict(condition)
+	}
+	return r
+}
+
+func (r region) restrict(c models.Condition) region {
+	switch c.Operator {
+	case models.LessThan:
+		r.upper(c.Value, true)
+	case models.LessThanOrEqual:
+		r.upper(c.Value, false)
+	case models.GreaterThan:
+		r.lower(c.Value, true)
+	case models.GreaterThanOrEqual:
+		r.lower(c.Value, false)
+	case models.Between:
+		if len(c.Values) == 2 {
+			r.lower(c.Values[0], c.Exclusive)
+			r.upper(c.Values[1], c.Exclusive)
+		}
+	case models.Equal:
+		r.allow([]float64{c.Value})
+	case models.In:
+		r.allow(c.Values)
+	case models.NotEqual:
+		r.excluded = append(r.excluded, c.Value)
+	}
+	return r
+}
+
+func (r *region) upper(value float64, open bool) {
+	if value < r.hi || (value == r.hi && open) {
+		r.hi, r.hiOpen = value, open
+	}
+}
+
+func (r *region) lower(value float64, open bool) {
+	if value > r.lo || (value == r.lo && open) {
+		r.lo, r.loOpen = value, open
+	}
+}
+
+func (r *region) allow(values []float64) {
+	if r.points == nil {
+		r.points = append([]float64{}, values...)
+		return
+	}
+	points := []float64{}
+	for _, p := range r.points {
+		if isIn(values, p) {
+			points = append(points, p)
+		}
+	}
+	r.points = points
+}
+
+func (r region) inBounds(value float64) bool {
+	if value < r.lo || (value == r.lo && r.loOpen) {
+		return false
+	}
+	return value < r.hi || (value == r.hi && !r.hiOpen)
+}
+
+func (r region) contains(value float64) bool {
+	return r.inBounds(value) && !isIn(r.excluded, value) && (r.points == nil || isIn(r.points, value))
+}
+
+func (r region) isEmpty() bool {
+	if r.points != nil {
+		for _, p := range r.points {
+			if r.contains(p) {
+				return false
+			}
+		}
+		return true
+	}
+	if r.lo > r.hi || (r.lo == r.hi && (r.loOpen || r.hiOpen)) {
+		return true
+	}
+	return r.lo == r.hi && isIn(r.excluded, r.lo)
+}
+
+// Every value of the (non empty) region satisfies the condition
+func (r region) within(c models.Condition) bool {
+	if r.points != nil || r.lo == r.hi {
+		values := r.points
+		if values == nil {
+			values = []float64{r.lo}
+		}
+		for _, v := range values {
+			if r.contains(v) && !isValidFact(c, v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch c.Operator {
+	case models.LessThan:
+		return r.hi < c.Value || (r.hi == c.Value && r.hiOpen)
+	case models.LessThanOrEqual:
+		return r.hi <= c.Value
+	case models.GreaterThan:
+		return r.lo > c.Value || (r.lo == c.Value && r.loOpen)
+	case models.GreaterThanOrEqual:
+		return r.lo >= c.Value
+	case models.Between:
+		if len(c.Values) != 2 {
+			return false
+		}
+		lower := models.Condition{Operator: models.GreaterThanOrEqual, Value: c.Values[0]}
+		upper := models.Condition{Operator: models.LessThanOrEqual, Value: c.Values[1]}
+		if c.Exclusive {
+			lower.Operator, upper.Operator = models.GreaterThan, models.LessThan
+		}
+		return r.within(lower) && r.within(upper)
+	case models.NotEqual:
+		return !r.contains(c.Value)
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package offer_utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+func cond(fact string, operator string, value float64) models.Condition {
+	return models.Condition{Fact: fact, Operator: operator, Value: value}
+}
+
+func TestLintOffers(t *testing.T) {
+	tt := []struct {
+		desc     string
+		offers   []models.Offer
+		expected []LintIssue
+	}{
+		{
+			desc: "with disjoint offers",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Conditions: []models.Condition{cond("distance", models.LessThan, 50), cond("weight", models.GreaterThan, 10)}},
+				{Code: "B", Discount: 0.1, Conditions: []models.Condition{cond("distance", models.GreaterThanOrEqual, 50)}},
+			},
+			expected: []LintIssue{},
+		},
+		{
+			desc: "with duplicate codes",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Conditions: []models.Condition{cond("distance", models.LessThan, 50)}},
+				{Code: "A", Discount: 0.2, Conditions: []models.Condition{cond("distance", models.GreaterThan, 50)}},
+			},
+			expected: []LintIssue{{"A", LintError, LintDuplicateCode, "code is defined 2 times, only the last one is used"}},
+		},
+		{
+			desc:   "with discount out of range",
+			offers: []models.Offer{{Code: "A", Discount: 1.5, Conditions: []models.Condition{cond("distance", models.LessThan, 50)}}},
+			expected: []LintIssue{
+				{"A", LintError, LintDiscountRange, "discount should be between 0 and 1, received 1.5"},
+			},
+		},
+		{
+			desc: "with unsatisfiable conditions",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Conditions: []models.Condition{cond("distance", models.LessThan, 50), cond("distance", models.GreaterThanOrEqual, 100)}},
+				{Code: "B", Discount: 0.1, Conditions: []models.Condition{cond("weight", models.LessThan, 50), cond("weight", models.GreaterThanOrEqual, 50)}},
+				{Code: "C", Discount: 0.1, Conditions: []models.Condition{{Fact: "weight", Operator: models.In, Values: []float64{10, 20}}, cond("weight", models.GreaterThan, 20)}},
+				{Code: "D", Discount: 0.1, Conditions: []models.Condition{cond("weight", models.Equal, 10), cond("weight", models.NotEqual, 10)}},
+				// at least 100 kg, distance below 5 or above 500
+				{Code: "E", Discount: 0.1, Conditions: []models.Condition{{Fact: "weight", Operator: models.Between, Values: []float64{10, 20}, Exclusive: true}, cond("weight", models.LessThanOrEqual, 10)}},
+			},
+			expected: []LintIssue{
+				{"A", LintError, LintUnsatisfiable, "distance conditions can never be satisfied together"},
+				{"B", LintError, LintUnsatisfiable, "weight conditions can never be satisfied together"},
+				{"C", LintError, LintUnsatisfiable, "weight conditions can never be satisfied together"},
+				{"D", LintError, LintUnsatisfiable, "weight conditions can never be satisfied together"},
+				{"E", LintError, LintUnsatisfiable, "weight conditions can never be satisfied together"},
+			},
+		},
+		{
+			desc: "with redundant conditions",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Conditions: []models.Condition{cond("distance", models.LessThan, 50), cond("distance", models.LessThan, 100)}},
+				{Code: "B", Discount: 0.1, Conditions: []models.Condition{cond("weight", models.GreaterThan, 200), cond("weight", models.GreaterThan, 200)}},
+				{Code: "C", Discount: 0.1, Conditions: []models.Condition{{Fact: "packageCount", Operator: models.In, Values: []float64{1, 2}}, cond("packageCount", models.LessThan, 5), cond("packageCount", models.NotEqual, 3)}},
+			},
+			expected: []LintIssue{
+				{"A", LintWarning, LintRedundant, `"distance lessThan 100" is implied by the other distance conditions`},
+				{"B", LintWarning, LintRedundant, `"weight greaterThan 200" is implied by the other weight conditions`},
+				{"C", LintWarning, LintRedundant, `"packageCount lessThan 5" is implied by the other packageCount conditions`},
+				{"C", LintWarning, LintRedundant, `"packageCount notEqual 3" is implied by the other packageCount conditions`},
+				{"A", LintWarning, LintOverlap, "weight/distance region overlaps with B"},
+				{"A", LintWarning, LintOverlap, "weight/distance region overlaps with C"},
+				{"B", LintWarning, LintOverlap, "weight/distance region overlaps with C"},
+			},
+		},
+		{
+			desc: "with overlapping offers",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Conditions: []models.Condition{cond("distance", models.LessThan, 200), {Fact: "weight", Operator: models.Between, Values: []float64{70, 200}}}},
+				{Code: "B", Discount: 0.1, Conditions: []models.Condition{{Fact: "distance", Operator: models.Between, Values: []float64{50, 150}}, cond("weight", models.GreaterThanOrEqual, 100)}},
+				{Code: "C", Discount: 0.1, Conditions: []models.Condition{cond("weight", models.LessThan, 70)}},
+				{Code: "D", Discount: 0.1, Conditions: []models.Condition{cond("distance", models.GreaterThanOrEqual, 200), cond("deliveryCost", models.GreaterThan, 100)}},
+			},
+			expected: []LintIssue{
+				{"A", LintWarning, LintOverlap, "weight/distance region overlaps with B"},
+				{"C", LintWarning, LintOverlap, "weight/distance region overlaps with D"},
+			},
+		},
+		{
+			desc: "with rules",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Conditions: []models.Condition{cond("weight", models.LessThan, 50)}},
+				{Code: "B", Discount: 0.1, Rules: &models.ConditionNode{All: []models.ConditionNode{leaf("weight", models.LessThan, 5)}}},
+				{Code: "C", Discount: 0.1, Rules: &models.ConditionNode{Any: []models.ConditionNode{leaf("weight", models.GreaterThan, 500)}}},
+				// at least 100 kg, along with distance below 10 or weight above 200
+				{Code: "D", Discount: 0.1, Conditions: []models.Condition{cond("weight", models.GreaterThanOrEqual, 100)}, Rules: &models.ConditionNode{Any: []models.ConditionNode{
+					leaf("distance", models.LessThan, 10),
+					{Not: &models.ConditionNode{All: []models.ConditionNode{leaf("weight", models.LessThanOrEqual, 200)}}},
+				}}},
+				// at least 100 kg, distance below 5 or above 500
+				{Code: "E", Discount: 0.1, Conditions: []models.Condition{cond("weight", models.GreaterThanOrEqual, 100)}, Rules: &models.ConditionNode{Not: &models.ConditionNode{Condition: &models.Condition{Fact: "distance", Operator: models.Between, Values: []float64{5, 500}}}}},
+			},
+			expected: []LintIssue{
+				{"A", LintWarning, LintOverlap, "weight/distance region overlaps with B"},
+				{"C", LintWarning, LintOverlap, "weight/distance region overlaps with D"},
+				{"C", LintWarning, LintOverlap, "weight/distance region overlaps with E"},
+				{"D", LintWarning, LintOverlap, "weight/distance region overlaps with E"},
+			},
+		},
+		{
+			desc: "with contradictions in rules",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Conditions: []models.Condition{cond("weight", models.LessThan, 50)}, Rules: &models.ConditionNode{All: []models.ConditionNode{
+					leaf("distance", models.LessThan, 10),
+					{All: []models.ConditionNode{leaf("weight", models.GreaterThan, 100)}},
+				}}},
+				{Code: "B", Discount: 0.1, Rules: &models.ConditionNode{Any: []models.ConditionNode{
+					{All: []models.ConditionNode{leaf("distance", models.LessThan, 10), leaf("distance", models.GreaterThan, 20)}},
+					{All: []models.ConditionNode{leaf("distance", models.LessThan, 100), leaf("distance", models.LessThan, 200)}},
+					// conditions under not are not checked
+					{Not: &models.ConditionNode{All: []models.ConditionNode{leaf("weight", models.LessThan, 1), leaf("weight", models.GreaterThan, 2)}}},
+				}}},
+			},
+			expected: []LintIssue{
+				{"A", LintError, LintUnsatisfiable, "weight conditions can never be satisfied together"},
+				{"B", LintError, LintUnsatisfiable, "distance conditions of rules.any[0] can never be satisfied together"},
+				{"B", LintWarning, LintRedundant, `"distance lessThan 200" is implied by the other distance conditions of rules.any[1]`},
+			},
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result := LintOffers(test.offers)
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %v, received %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestHasLintErrors(t *testing.T) {
+	if HasLintErrors([]LintIssue{{Severity: LintWarning}}) {
+		t.Error("warnings are not errors")
+	}
+	if !HasLintErrors([]LintIssue{{Severity: LintWarning}, {Severity: LintError}}) {
+		t.Error("should have errors")
+	}
+}
@@ -18,10 +18,152 @@ func isValidFact(condition models.Condition, value float64) bool {
 		isValid = value >= condition.Value
 	case models.LessThanOrEqual:
 		isValid = value <= condition.Value
+	case models.GreaterThan:
+		isValid = value > condition.Value
+	case models.Equal:
+		isValid = value == condition.Value
+	case models.NotEqual:
+		isValid = value != condition.Value
+	case models.Between:
+		isValid = isBetween(condition, value)
+	case models.In:
+		isValid = isIn(condition.Values, value)
 	}
 	return isValid
 }
 
+func isBetween(condition models.Condition, value float64) bool {
+	if len(condition.Values) != 2 {
+		return false
+	}
+	lower, upper := condition.Values[0], condition.Values[1]
+	if condition.Exclusive {
+		return value > lower && value < upper
+	}
+	return value >= lower && value <= upper
+}
+
+func isIn(values []float64, value float64) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Rejects conditions which can not be evaluated, instead of silently treating them as not satisfied
+func ValidateOffers(offers []models.Offer) error {
+	for _, offer := range offers {
+		if len(offer.Conditions) == 0 && offer.Rules == nil {
+			return error_utils.ErrOfferRule(offer.Code, "requires conditions or rules")
+		}
+		if err := validateRule(offer.Code, offer.ConditionTree()); err != nil {
+			return err
+		}
+		if err := validateActivity(offer); err != nil {
+			return err
+		}
+		if err := validateDiscount(offer); err != nil {
+			return err
+		}
+		if err := validateStacking(offer); err != nil {
+			return err
+		}
+		if err := validateRedemptionLimits(offer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRedemptionLimits(offer models.Offer) error {
+	if offer.MaxRedemptions < 0 || offer.MaxPerCustomer < 0 {
+		return error_utils.ErrOfferRedemptionLimit(offer.Code, "redemption limits should not be negative")
+	}
+	if offer.MaxRedemptions > 0 && offer.MaxPerCustomer > offer.MaxRedemptions {
+		return error_utils.ErrOfferRedemptionLimit(offer.Code, "maxPerCustomer should not exceed maxRedemptions")
+	}
+	return nil
+}
+
+// Every node of the tree should be exactly one of all, any, not or a condition
+func validateRule(code models.OfferCode, node models.ConditionNode) error {
+	kinds := 0
+	if node.IsLeaf() {
+		kinds++
+	}
+	if node.Not != nil {
+		kinds++
+	}
+	if node.All != nil {
+		kinds++
+	}
+	if node.Any != nil {
+		kinds++
+	}
+	if kinds != 1 {
+		return error_utils.ErrOfferRule(code, "node should be exactly one of all, any, not or a condition")
+	}
+
+	switch {
+	case node.IsLeaf():
+		return validateCondition(code, *node.Condition)
+	case node.Not != nil:
+		return validateRule(code, *node.Not)
+	}
+	children := node.All
+	if node.Any != nil {
+		children = node.Any
+	}
+	if len(children) == 0 {
+		return error_utils.ErrOfferRule(code, "group should have at least one node")
+	}
+	for _, child := range children {
+		if err := validateRule(code, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCondition(code models.OfferCode, condition models.Condition) error {
+	definition, ok := LookupFact(condition.Fact)
+	if !ok {
+		return error_utils.ErrOfferUnknownFact(code, condition.Fact)
+	}
+	if !models.IsKnownOperator(condition.Operator) {
+		return error_utils.ErrOfferUnknownOperator(code, condition.Operator)
+	}
+	if definition.Kind == FactKindText {
+		if condition.Operator != models.Equal && condition.Operator != models.NotEqual {
+			return error_utils.ErrOfferConditionValues(code, condition.Operator, "is not supported by text fact "+condition.Fact)
+		}
+		return nil
+	}
+	if condition.Text != "" {
+		return error_utils.ErrOfferConditionValues(code, condition.Operator, "does not accept text for number fact "+condition.Fact)
+	}
+	switch condition.Operator {
+	case models.Between:
+		if len(condition.Values) != 2 {
+			return error_utils.ErrOfferConditionValues(code, condition.Operator, "requires lower and upper bounds")
+		}
+		if condition.Values[0] > condition.Values[1] {
+			return error_utils.ErrOfferConditionValues(code, condition.Operator, "lower bound should not exceed upper bound")
+		}
+	case models.In:
+		if len(condition.Values) == 0 {
+			return error_utils.ErrOfferConditionValues(code, condition.Operator, "requires at least one value")
+		}
+	default:
+		if len(condition.Values) != 0 {
+			return error_utils.ErrOfferConditionValues(code, condition.Operator, "does not accept values")
+		}
+	}
+	return nil
+}
+
 // If any one of the condition fails to satisfy, then the offer is not applicable
 func IsOfferApplicable(conditions []models.Condition, facts models.Facts, fact models.Fact) bool {
 	// When both conditions and facts empty then offer is applicable
@@ -37,25 +179,100 @@ func IsOfferApplicable(conditions []models.Condition, facts models.Facts, fact m
 	}
 	var isApplicable bool = true
 	for _, condition := range conditions {
-		var isValid bool
-		switch condition.Fact {
-		case "distance":
-			isValid = isValidFact(condition, fact.Distance)
-		case "weight":
-			isValid = isValidFact(condition, fact.Weight)
-		}
-		isApplicable = isValid && isApplicable
+		isApplicable = isConditionSatisfied(condition, fact) && isApplicable
 	}
 	return isApplicable
 }
 
+// Conditions on unknown facts are never satisfied (rejected while loading offers)
+func isConditionSatisfied(condition models.Condition, fact models.Fact) bool {
+	definition, ok := LookupFact(condition.Fact)
+	if !ok {
+		return false
+	}
+	if definition.Kind == FactKindText {
+		return isValidText(condition, definition.Text(fact))
+	}
+	return isValidFact(condition, definition.Number(fact))
+}
+
+func isValidText(condition models.Condition, value string) bool {
+	switch condition.Operator {
+	case models.Equal:
+		return value == condition.Text
+	case models.NotEqual:
+		return value != condition.Text
+	}
+	return false
+}
+
+// Evaluates a condition tree
+// all - every node should be satisfied, any - at least one node should be satisfied, not - node should not be satisfied
+func IsRuleSatisfied(node models.ConditionNode, fact models.Fact) bool {
+	switch {
+	case node.IsLeaf():
+		return isConditionSatisfied(*node.Condition, fact)
+	case node.Not != nil:
+		return !IsRuleSatisfied(*node.Not, fact)
+	case len(node.Any) > 0:
+		for _, child := range node.Any {
+			if IsRuleSatisfied(child, fact) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, child := range node.All {
+		if !IsRuleSatisfied(child, fact) {
+			return false
+		}
+	}
+	return true
+}
+
+// Offer is applicable when all the (flat) conditions and the rules are satisfied
+func IsApplicable(offer models.Offer, fact models.Fact) bool {
+	if len(offer.Conditions) == 0 && offer.Rules == nil {
+		return false
+	}
+	if offer.Rules == nil {
+		return IsOfferApplicable(offer.Conditions, offer.FactsToValidate(), fact)
+	}
+	if fact == (models.Fact{}) {
+		return false
+	}
+	return IsRuleSatisfied(offer.ConditionTree(), fact)
+}
+
+// Loads offers of the file, in the format of its extension (json, yaml or toml)
 func LoadOffers(filename string) ([]models.Offer, error) {
 	if len(strings.TrimSpace(filename)) == 0 {
 		return nil, error_utils.ErrMissingInput
 	}
-	var OffersSlice []models.Offer
 	content, err := ioutil.ReadFile(filename)
 
+	if err != nil {
+		return nil, err
+	}
+	return DecodeOffersFormat(content, FormatOf(filename))
+}
+
+// Loads offers of the file as they are, without validating them (ex: to lint them)
+func LoadOffersLenient(filename string) ([]models.Offer, error) {
+	if len(strings.TrimSpace(filename)) == 0 {
+		return nil, error_utils.ErrMissingInput
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeOffersFormatLenient(content, FormatOf(filename))
+}
+
+// Decodes offers (json array) regardless of where they are stored, validated against the schema and the offer engine
+func DecodeOffers(content []byte) ([]models.Offer, error) {
+	var OffersSlice []models.Offer
+	err := ValidateOffersSchema(content)
 	if err != nil {
 		return nil, err
 	}
@@ -64,5 +281,10 @@ func LoadOffers(filename string) ([]models.Offer, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	err = ValidateOffers(OffersSlice)
+	if err != nil {
+		return nil, err
+	}
 	return OffersSlice, nil
 }
@@ -163,6 +163,279 @@ func TestIsOfferApplicable(t *testing.T) {
 	}
 }
 
+func leaf(fact string, operator string, value float64) models.ConditionNode {
+	return models.ConditionNode{Condition: &models.Condition{Fact: fact, Operator: operator, Value: value}}
+}
+
+func TestIsRuleSatisfied(t *testing.T) {
+	// distance < 50 OR weight > 200
+	nearOrHeavy := models.ConditionNode{Any: []models.ConditionNode{
+		leaf("distance", models.LessThan, 50),
+		leaf("weight", models.GreaterThan, 200),
+	}}
+
+	tt := []struct {
+		desc     string
+		rule     models.ConditionNode
+		fact     models.Fact
+		expected bool
+	}{
+		{desc: "any (first node satisfied)", rule: nearOrHeavy, fact: models.Fact{Distance: 10, Weight: 10}, expected: true},
+		{desc: "any (second node satisfied)", rule: nearOrHeavy, fact: models.Fact{Distance: 100, Weight: 250}, expected: true},
+		{desc: "any (none satisfied)", rule: nearOrHeavy, fact: models.Fact{Distance: 100, Weight: 10}, expected: false},
+		{
+			desc:     "all",
+			rule:     models.ConditionNode{All: []models.ConditionNode{leaf("distance", models.LessThan, 50), leaf("weight", models.GreaterThan, 5)}},
+			fact:     models.Fact{Distance: 10, Weight: 10},
+			expected: true,
+		},
+		{
+			desc:     "all (one not satisfied)",
+			rule:     models.ConditionNode{All: []models.ConditionNode{leaf("distance", models.LessThan, 50), leaf("weight", models.GreaterThan, 50)}},
+			fact:     models.Fact{Distance: 10, Weight: 10},
+			expected: false,
+		},
+		{desc: "not", rule: models.ConditionNode{Not: &nearOrHeavy}, fact: models.Fact{Distance: 100, Weight: 10}, expected: true},
+		{desc: "not (node satisfied)", rule: models.ConditionNode{Not: &nearOrHeavy}, fact: models.Fact{Distance: 10, Weight: 10}, expected: false},
+		{
+			desc: "nested groups",
+			rule: models.ConditionNode{All: []models.ConditionNode{
+				nearOrHeavy,
+				{Not: &models.ConditionNode{Condition: &models.Condition{Fact: "weight", Operator: models.Equal, Value: 10}}},
+			}},
+			fact:     models.Fact{Distance: 10, Weight: 10},
+			expected: false,
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result := IsRuleSatisfied(test.rule, test.fact)
+			if test.expected != result {
+				t.Errorf("expected %t received %t", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsApplicable(t *testing.T) {
+	nearOrHeavy := &models.ConditionNode{Any: []models.ConditionNode{
+		leaf("distance", models.LessThan, 50),
+		leaf("weight", models.GreaterThan, 200),
+	}}
+
+	tt := []struct {
+		desc     string
+		offer    models.Offer
+		fact     models.Fact
+		expected bool
+	}{
+		{
+			desc:     "flat conditions only",
+			offer:    models.Offer{Code: "A", Conditions: []models.Condition{{Fact: "distance", Operator: models.LessThan, Value: 50}}},
+			fact:     models.Fact{Distance: 10, Weight: 10},
+			expected: true,
+		},
+		{
+			desc:     "rules only",
+			offer:    models.Offer{Code: "A", Rules: nearOrHeavy},
+			fact:     models.Fact{Distance: 100, Weight: 250},
+			expected: true,
+		},
+		{
+			desc: "conditions and rules satisfied",
+			offer: models.Offer{
+				Code:       "A",
+				Conditions: []models.Condition{{Fact: "distance", Operator: models.GreaterThan, Value: 20}},
+				Rules:      nearOrHeavy,
+			},
+			fact:     models.Fact{Distance: 30, Weight: 10},
+			expected: true,
+		},
+		{
+			desc: "rules satisfied but not conditions",
+			offer: models.Offer{
+				Code:       "A",
+				Conditions: []models.Condition{{Fact: "distance", Operator: models.GreaterThan, Value: 20}},
+				Rules:      nearOrHeavy,
+			},
+			fact:     models.Fact{Distance: 10, Weight: 10},
+			expected: false,
+		},
+		{
+			desc:     "when values are not provided",
+			offer:    models.Offer{Code: "A", Rules: &models.ConditionNode{Not: nearOrHeavy}},
+			fact:     models.Fact{},
+			expected: false,
+		},
+		{
+			desc:     "when offer is not available",
+			offer:    models.Offer{},
+			fact:     models.Fact{Distance: 10, Weight: 10},
+			expected: false,
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result := IsApplicable(test.offer, test.fact)
+			if test.expected != result {
+				t.Errorf("expected %t received %t", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsValidFact(t *testing.T) {
+	tt := []struct {
+		desc      string
+		condition models.Condition
+		value     float64
+		expected  bool
+	}{
+		{desc: "lessThan", condition: models.Condition{Operator: models.LessThan, Value: 10}, value: 9, expected: true},
+		{desc: "lessThan (equal)", condition: models.Condition{Operator: models.LessThan, Value: 10}, value: 10, expected: false},
+		{desc: "lessThanOrEqual", condition: models.Condition{Operator: models.LessThanOrEqual, Value: 10}, value: 10, expected: true},
+		{desc: "greaterThan", condition: models.Condition{Operator: models.GreaterThan, Value: 10}, value: 11, expected: true},
+		{desc: "greaterThan (equal)", condition: models.Condition{Operator: models.GreaterThan, Value: 10}, value: 10, expected: false},
+		{desc: "greaterThanOrEqual", condition: models.Condition{Operator: models.GreaterThanOrEqual, Value: 10}, value: 10, expected: true},
+		{desc: "equal", condition: models.Condition{Operator: models.Equal, Value: 10}, value: 10, expected: true},
+		{desc: "equal (different)", condition: models.Condition{Operator: models.Equal, Value: 10}, value: 10.5, expected: false},
+		{desc: "notEqual", condition: models.Condition{Operator: models.NotEqual, Value: 10}, value: 10.5, expected: true},
+		{desc: "notEqual (same)", condition: models.Condition{Operator: models.NotEqual, Value: 10}, value: 10, expected: false},
+		{desc: "between (inclusive lower bound)", condition: models.Condition{Operator: models.Between, Values: []float64{10, 20}}, value: 10, expected: true},
+		{desc: "between (inclusive upper bound)", condition: models.Condition{Operator: models.Between, Values: []float64{10, 20}}, value: 20, expected: true},
+		{desc: "between (outside)", condition: models.Condition{Operator: models.Between, Values: []float64{10, 20}}, value: 20.1, expected: false},
+		{desc: "between (exclusive lower bound)", condition: models.Condition{Operator: models.Between, Values: []float64{10, 20}, Exclusive: true}, value: 10, expected: false},
+		{desc: "between (exclusive upper bound)", condition: models.Condition{Operator: models.Between, Values: []float64{10, 20}, Exclusive: true}, value: 20, expected: false},
+		{desc: "between (exclusive within)", condition: models.Condition{Operator: models.Between, Values: []float64{10, 20}, Exclusive: true}, value: 15, expected: true},
+		{desc: "between (without bounds)", condition: models.Condition{Operator: models.Between}, value: 15, expected: false},
+		{desc: "in", condition: models.Condition{Operator: models.In, Values: []float64{5, 10, 15}}, value: 10, expected: true},
+		{desc: "in (not in set)", condition: models.Condition{Operator: models.In, Values: []float64{5, 10, 15}}, value: 11, expected: false},
+		{desc: "unknown operator", condition: models.Condition{Operator: "greaterThanOrEqualTo", Value: 10}, value: 11, expected: false},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result := isValidFact(test.condition, test.value)
+			if test.expected != result {
+				t.Errorf("expected %t received %t", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateOffers(t *testing.T) {
+	tt := []struct {
+		desc       string
+		conditions []models.Condition
+		expected   error
+	}{
+		{
+			desc: "with valid conditions",
+			conditions: []models.Condition{
+				{Fact: "distance", Operator: models.GreaterThan, Value: 10},
+				{Fact: "distance", Operator: models.Between, Values: []float64{10, 20}},
+				{Fact: "weight", Operator: models.In, Values: []float64{5}},
+			},
+		},
+		{
+			desc:       "with unknown operator",
+			conditions: []models.Condition{{Fact: "distance", Operator: "greaterThanOrEqualTo", Value: 10}},
+			expected:   error_utils.ErrOfferUnknownOperator("A", "greaterThanOrEqualTo"),
+		},
+		{
+			desc:       "between without bounds",
+			conditions: []models.Condition{{Fact: "distance", Operator: models.Between, Values: []float64{10}}},
+			expected:   error_utils.ErrOfferConditionValues("A", models.Between, "requires lower and upper bounds"),
+		},
+		{
+			desc:       "between with reversed bounds",
+			conditions: []models.Condition{{Fact: "distance", Operator: models.Between, Values: []float64{20, 10}}},
+			expected:   error_utils.ErrOfferConditionValues("A", models.Between, "lower bound should not exceed upper bound"),
+		},
+		{
+			desc:       "in without values",
+			conditions: []models.Condition{{Fact: "distance", Operator: models.In}},
+			expected:   error_utils.ErrOfferConditionValues("A", models.In, "requires at least one value"),
+		},
+		{
+			desc:       "comparison with values",
+			conditions: []models.Condition{{Fact: "distance", Operator: models.Equal, Values: []float64{10}}},
+			expected:   error_utils.ErrOfferConditionValues("A", models.Equal, "does not accept values"),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffers([]models.Offer{{Code: "A", Discount: 0.1, Conditions: test.conditions}})
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidateOfferRules(t *testing.T) {
+	tt := []struct {
+		desc     string
+		offer    models.Offer
+		expected error
+	}{
+		{
+			desc: "with valid rules",
+			offer: models.Offer{Code: "A", Rules: &models.ConditionNode{Any: []models.ConditionNode{
+				leaf("distance", models.LessThan, 50),
+				{Not: &models.ConditionNode{All: []models.ConditionNode{leaf("weight", models.GreaterThan, 200)}}},
+			}}},
+		},
+		{
+			desc:     "without conditions and rules",
+			offer:    models.Offer{Code: "A"},
+			expected: error_utils.ErrOfferRule("A", "requires conditions or rules"),
+		},
+		{
+			desc:     "with empty group",
+			offer:    models.Offer{Code: "A", Rules: &models.ConditionNode{Any: []models.ConditionNode{}}},
+			expected: error_utils.ErrOfferRule("A", "group should have at least one node"),
+		},
+		{
+			desc:     "with empty node",
+			offer:    models.Offer{Code: "A", Rules: &models.ConditionNode{}},
+			expected: error_utils.ErrOfferRule("A", "node should be exactly one of all, any, not or a condition"),
+		},
+		{
+			desc: "with group and condition in the same node",
+			offer: models.Offer{Code: "A", Rules: &models.ConditionNode{
+				Any:       []models.ConditionNode{leaf("distance", models.LessThan, 50)},
+				Condition: &models.Condition{Fact: "weight", Operator: models.LessThan, Value: 50},
+			}},
+			expected: error_utils.ErrOfferRule("A", "node should be exactly one of all, any, not or a condition"),
+		},
+		{
+			desc:     "with unknown operator in nested node",
+			offer:    models.Offer{Code: "A", Rules: &models.ConditionNode{Not: &models.ConditionNode{Condition: &models.Condition{Fact: "weight", Operator: "above"}}}},
+			expected: error_utils.ErrOfferUnknownOperator("A", "above"),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffers([]models.Offer{test.offer})
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
+
 func TestLoadOffers(t *testing.T) {
 	// TODO: not mocking `ioutil.ReadFile`
 	// This is a proper solution for now, to not to impose deps on loadOffers() function
@@ -182,6 +455,21 @@ func TestLoadOffers(t *testing.T) {
 			offersFile: "./testdata/offers.json",
 			expected:   []models.Offer{},
 		},
+		{
+			desc:       "when offers file has condition groups",
+			offersFile: "./testdata/rules.json",
+			expected:   []models.Offer{{}, {}},
+		},
+		{
+			desc:        "when offers file has unknown operator",
+			offersFile:  "./testdata/unknown_operator.json",
+			expectedErr: SchemaErrors{{Path: "$[0].conditions[0].operator", Line: 8, Column: 29, Message: `unknown operator "greaterThanOrEqualTo", expected one of lessThan, greaterThanOrEqual, lessThanOrEqual, greaterThan, equal, notEqual, between, in`}},
+		},
+		{
+			desc:        "when offers file has no offers",
+			offersFile:  "./testdata/offers.json",
+			expectedErr: SchemaErrors{{Path: "$", Line: 1, Column: 1, Message: "should have at least 1 offers, received 0"}},
+		},
 		{
 			desc:        "when offers file is not available",
 			offersFile:  "./testdata/nooffers.json",
@@ -204,3 +492,72 @@ func TestLoadOffers(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadOffersWithRules(t *testing.T) {
+	offers, err := LoadOffers("./testdata/rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		desc     string
+		offer    models.Offer
+		fact     models.Fact
+		expected bool
+	}{
+		{desc: "OFR004 near", offer: offers[0], fact: models.Fact{Distance: 10, Weight: 10}, expected: true},
+		{desc: "OFR004 heavy", offer: offers[0], fact: models.Fact{Distance: 100, Weight: 250}, expected: true},
+		{desc: "OFR004 neither near nor heavy", offer: offers[0], fact: models.Fact{Distance: 100, Weight: 150}, expected: false},
+		{desc: "OFR005 without flat conditions", offer: offers[1], fact: models.Fact{Distance: 60, Weight: 10}, expected: true},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result := IsApplicable(test.offer, test.fact)
+			if test.expected != result {
+				t.Errorf("expected %t received %t", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateOffersRedemptionLimits(t *testing.T) {
+	conditions := []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 10}}
+	tt := []struct {
+		desc     string
+		offer    models.Offer
+		expected error
+	}{
+		{
+			desc:  "with redemption limits",
+			offer: models.Offer{Code: "A", Conditions: conditions, MaxRedemptions: 500, MaxPerCustomer: 3},
+		},
+		{
+			desc:  "with per customer limit only",
+			offer: models.Offer{Code: "A", Conditions: conditions, MaxPerCustomer: 3},
+		},
+		{
+			desc:     "with negative limit",
+			offer:    models.Offer{Code: "A", Conditions: conditions, MaxRedemptions: -1},
+			expected: error_utils.ErrOfferRedemptionLimit("A", "redemption limits should not be negative"),
+		},
+		{
+			desc:     "with per customer limit above total",
+			offer:    models.Offer{Code: "A", Conditions: conditions, MaxRedemptions: 2, MaxPerCustomer: 3},
+			expected: error_utils.ErrOfferRedemptionLimit("A", "maxPerCustomer should not exceed maxRedemptions"),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffers([]models.Offer{test.offer})
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package offer_utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+const timeOfDayLayout = "15:04"
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Whether the offer is active at the given time (validity period and schedule),
+// otherwise status (expired or not active) along with the reason
+func IsActive(offer models.Offer, now time.Time) (bool, models.DiscountStatus, string) {
+	if offer.ValidFrom != nil && now.Before(*offer.ValidFrom) {
+		return false, models.DiscountNotActive, fmt.Sprintf("active from %s", offer.ValidFrom.Format(time.RFC3339))
+	}
+	if offer.ValidUntil != nil && !now.Before(*offer.ValidUntil) {
+		return false, models.DiscountExpired, fmt.Sprintf("expired at %s", offer.ValidUntil.Format(time.RFC3339))
+	}
+	if offer.Schedule == nil {
+		return true, "", ""
+	}
+
+	schedule := offer.Schedule
+	if len(schedule.Days) > 0 && !isScheduledDay(schedule.Days, now.Weekday()) {
+		return false, models.DiscountNotActive, fmt.Sprintf("active only on %s", strings.Join(schedule.Days, ", "))
+	}
+	if schedule.From != "" && !isScheduledTime(schedule.From, schedule.Until, now) {
+		return false, models.DiscountNotActive, fmt.Sprintf("active only between %s and %s", schedule.From, schedule.Until)
+	}
+	return true, "", ""
+}
+
+func isScheduledDay(days []string, day time.Weekday) bool {
+	for _, d := range days {
+		if weekdays[d] == day {
+			return true
+		}
+	}
+	return false
+}
+
+func isScheduledTime(from string, until string, now time.Time) bool {
+	start, _ := minuteOfDay(from)
+	end, _ := minuteOfDay(until)
+	minute := now.Hour()*60 + now.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	// spans midnight, ex: 22:00 to 02:00
+	return minute >= start || minute < end
+}
+
+func minuteOfDay(value string) (int, error) {
+	t, err := time.Parse(timeOfDayLayout, value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validity period should not be empty, schedule should have known days and time of day (HH:MM) window
+func validateActivity(offer models.Offer) error {
+	if offer.ValidFrom != nil && offer.ValidUntil != nil && !offer.ValidFrom.Before(*offer.ValidUntil) {
+		return error_utils.ErrOfferSchedule(offer.Code, "validFrom should be before validUntil")
+	}
+	if offer.Schedule == nil {
+		return nil
+	}
+	if reason := scheduleProblem(*offer.Schedule); reason != "" {
+		return error_utils.ErrOfferSchedule(offer.Code, reason)
+	}
+	return nil
+}
+
+// Describes what is wrong with the schedule, empty when it is valid
+func scheduleProblem(schedule models.OfferSchedule) string {
+	if len(schedule.Days) == 0 && schedule.From == "" && schedule.Until == "" {
+		return "schedule requires days or from and until"
+	}
+	for _, day := range schedule.Days {
+		if _, ok := weekdays[day]; !ok {
+			return fmt.Sprintf("unknown day %q, expected one of monday, tuesday, wednesday, thursday, friday, saturday, sunday", day)
+		}
+	}
+	if schedule.From == "" && schedule.Until == "" {
+		return ""
+	}
+	start, err := minuteOfDay(schedule.From)
+	if err != nil {
+		return fmt.Sprintf("from should be time of day (HH:MM), received %q", schedule.From)
+	}
+	end, err := minuteOfDay(schedule.Until)
+	if err != nil {
+		return fmt.Sprintf("until should be time of day (HH:MM), received %q", schedule.Until)
+	}
+	if start == end {
+		return "from and until should not be the same"
+	}
+	return ""
+}
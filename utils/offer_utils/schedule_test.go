@@ -0,0 +1,148 @@
+package offer_utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+func at(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func TestIsActive(t *testing.T) {
+	// saturday
+	now := *at("2026-10-17T10:30:00Z")
+	tt := []struct {
+		desc           string
+		offer          models.Offer
+		expectedStatus models.DiscountStatus
+		expectedReason string
+	}{
+		{
+			desc:  "without validity period and schedule",
+			offer: models.Offer{},
+		},
+		{
+			desc:  "within validity period",
+			offer: models.Offer{ValidFrom: at("2026-10-17T10:30:00Z"), ValidUntil: at("2026-10-17T10:31:00Z")},
+		},
+		{
+			desc:           "before validity period",
+			offer:          models.Offer{ValidFrom: at("2026-12-01T00:00:00Z")},
+			expectedStatus: models.DiscountNotActive,
+			expectedReason: "active from 2026-12-01T00:00:00Z",
+		},
+		{
+			desc:           "after validity period",
+			offer:          models.Offer{ValidUntil: at("2026-10-17T10:30:00Z")},
+			expectedStatus: models.DiscountExpired,
+			expectedReason: "expired at 2026-10-17T10:30:00Z",
+		},
+		{
+			desc:  "on scheduled day",
+			offer: models.Offer{Schedule: &models.OfferSchedule{Days: []string{"saturday", "sunday"}}},
+		},
+		{
+			desc:           "on other days",
+			offer:          models.Offer{Schedule: &models.OfferSchedule{Days: []string{"monday", "friday"}}},
+			expectedStatus: models.DiscountNotActive,
+			expectedReason: "active only on monday, friday",
+		},
+		{
+			desc:  "within time of day window",
+			offer: models.Offer{Schedule: &models.OfferSchedule{From: "10:30", Until: "10:31"}},
+		},
+		{
+			desc:           "outside time of day window",
+			offer:          models.Offer{Schedule: &models.OfferSchedule{Days: []string{"saturday"}, From: "09:00", Until: "10:30"}},
+			expectedStatus: models.DiscountNotActive,
+			expectedReason: "active only between 09:00 and 10:30",
+		},
+		{
+			desc:  "within window spanning midnight",
+			offer: models.Offer{Schedule: &models.OfferSchedule{From: "22:00", Until: "11:00"}},
+		},
+		{
+			desc:           "outside window spanning midnight",
+			offer:          models.Offer{Schedule: &models.OfferSchedule{From: "22:00", Until: "02:00"}},
+			expectedStatus: models.DiscountNotActive,
+			expectedReason: "active only between 22:00 and 02:00",
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			active, status, reason := IsActive(test.offer, now)
+			if active != (test.expectedStatus == "") {
+				t.Errorf("expected active %v, received %v", test.expectedStatus == "", active)
+			}
+			if status != test.expectedStatus || reason != test.expectedReason {
+				t.Errorf("expected %s (%s), received %s (%s)", test.expectedStatus, test.expectedReason, status, reason)
+			}
+		})
+	}
+}
+
+func TestValidateOffersActivity(t *testing.T) {
+	conditions := []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 10}}
+	tt := []struct {
+		desc     string
+		offer    models.Offer
+		expected error
+	}{
+		{
+			desc:  "with validity period and schedule",
+			offer: models.Offer{Code: "A", Conditions: conditions, ValidFrom: at("2026-12-01T00:00:00Z"), ValidUntil: at("2027-01-01T00:00:00Z"), Schedule: &models.OfferSchedule{Days: []string{"sunday"}, From: "09:00", Until: "18:00"}},
+		},
+		{
+			desc:     "with empty validity period",
+			offer:    models.Offer{Code: "A", Conditions: conditions, ValidFrom: at("2027-01-01T00:00:00Z"), ValidUntil: at("2027-01-01T00:00:00Z")},
+			expected: error_utils.ErrOfferSchedule("A", "validFrom should be before validUntil"),
+		},
+		{
+			desc:     "with empty schedule",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Schedule: &models.OfferSchedule{}},
+			expected: error_utils.ErrOfferSchedule("A", "schedule requires days or from and until"),
+		},
+		{
+			desc:     "with unknown day",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Schedule: &models.OfferSchedule{Days: []string{"sun"}}},
+			expected: error_utils.ErrOfferSchedule("A", `unknown day "sun", expected one of monday, tuesday, wednesday, thursday, friday, saturday, sunday`),
+		},
+		{
+			desc:     "without until",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Schedule: &models.OfferSchedule{From: "09:00"}},
+			expected: error_utils.ErrOfferSchedule("A", `until should be time of day (HH:MM), received ""`),
+		},
+		{
+			desc:     "with invalid time of day",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Schedule: &models.OfferSchedule{From: "9am", Until: "18:00"}},
+			expected: error_utils.ErrOfferSchedule("A", `from should be time of day (HH:MM), received "9am"`),
+		},
+		{
+			desc:     "with empty time of day window",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Schedule: &models.OfferSchedule{From: "09:00", Until: "09:00"}},
+			expected: error_utils.ErrOfferSchedule("A", "from and until should not be the same"),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffers([]models.Offer{test.offer})
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,528 @@
+package offer_utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+// Limits of the offers schema (same as scripts/src/schema.ts)
+const (
+	MinOffers     = 1
+	MaxOffers     = 50
+	MinConditions = 1
+	MaxConditions = 30
+	// percentage discount
+	MinDiscountRatio = 0
+	MaxDiscountRatio = 1
+)
+
+// Schema violation, located by its JSON path and position (line, column) in the source
+type SchemaError struct {
+	Path    string
+	Line    int
+	Column  int // 0 when the source reports the line only
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("%s (line %d): %s", e.Path, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s (line %d, column %d): %s", e.Path, e.Line, e.Column, e.Message)
+}
+
+// All schema violations of an offers document
+type SchemaErrors []SchemaError
+
+func (e SchemaErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+type nodeKind string
+
+const (
+	kindNull   nodeKind = "null"
+	kindBool   nodeKind = "boolean"
+	kindNumber nodeKind = "number"
+	kindString nodeKind = "string"
+	kindArray  nodeKind = "array"
+	kindObject nodeKind = "object"
+)
+
+type member struct {
+	key    string
+	line   int
+	column int
+	value  *schemaNode
+}
+
+// Decoded value (of any offers format) along with its position in the source, so that violations can be located
+type schemaNode struct {
+	kind    nodeKind
+	line    int
+	column  int
+	members []member // object
+	items   []*schemaNode
+	number  float64
+	text    string
+	boolean bool
+}
+
+func (n *schemaNode) get(key string) *schemaNode {
+	for _, m := range n.members {
+		if m.key == key {
+			return m.value
+		}
+	}
+	return nil
+}
+
+// Validates offers document (json) against the offers schema, see ValidateOffersSchemaFormat for other formats
+// Returns SchemaErrors listing every violation, or a syntax error
+func ValidateOffersSchema(content []byte) error {
+	root, err := parseJSON(content)
+	if err != nil {
+		return err
+	}
+	return validateOffersNode(root)
+}
+
+func validateOffersNode(root *schemaNode) error {
+	v := &schemaValidator{}
+	v.offers(root)
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+type schemaValidator struct {
+	errs SchemaErrors
+}
+
+func (v *schemaValidator) fail(path string, line int, column int, format string, args ...interface{}) {
+	v.errs = append(v.errs, SchemaError{Path: path, Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) expect(path string, node *schemaNode, kind nodeKind) bool {
+	if node.kind != kind {
+		v.fail(path, node.line, node.column, "should be %s, received %s", kind, node.kind)
+		return false
+	}
+	return true
+}
+
+// Rejects unknown and duplicate properties of an object
+func (v *schemaValidator) properties(path string, node *schemaNode, allowed ...string) {
+	seen := make(map[string]bool)
+	for _, m := range node.members {
+		if seen[m.key] {
+			v.fail(path+"."+m.key, m.line, m.column, "duplicate property %q", m.key)
+		}
+		seen[m.key] = true
+		if !contains(allowed, m.key) {
+			v.fail(path+"."+m.key, m.line, m.column, "unknown property %q", m.key)
+		}
+	}
+}
+
+func (v *schemaValidator) required(path string, node *schemaNode, keys ...string) {
+	for _, key := range keys {
+		if node.get(key) == nil {
+			v.fail(path, node.line, node.column, "missing required property %q", key)
+		}
+	}
+}
+
+func (v *schemaValidator) itemCount(path string, node *schemaNode, noun string, min int, max int) {
+	if len(node.items) < min {
+		v.fail(path, node.line, node.column, "should have at least %d %s, received %d", min, noun, len(node.items))
+	}
+	if max > 0 && len(node.items) > max {
+		v.fail(path, node.line, node.column, "should have at most %d %s, received %d", max, noun, len(node.items))
+	}
+}
+
+func (v *schemaValidator) offers(root *schemaNode) {
+	path := "$"
+	if !v.expect(path, root, kindArray) {
+		return
+	}
+	v.itemCount(path, root, "offers", MinOffers, MaxOffers)
+	for i, offer := range root.items {
+		v.offer(fmt.Sprintf("%s[%d]", path, i), offer)
+	}
+}
+
+func (v *schemaValidator) offer(path string, node *schemaNode) {
+	if !v.expect(path, node, kindObject) {
+		return
+	}
+	v.properties(path, node, "code", "discount", "discountType", "maxDiscount", "minDiscount", "conditions", "rules", "validFrom", "validUntil", "schedule", "stacking", "priority", "stackMode", "autoApply", "maxRedemptions", "maxPerCustomer")
+	v.required(path, node, "code", "discount")
+	if node.get("conditions") == nil && node.get("rules") == nil {
+		v.fail(path, node.line, node.column, "requires conditions or rules")
+	}
+
+	if code := node.get("code"); code != nil {
+		v.expect(path+".code", code, kindString)
+	}
+	v.discount(path, node)
+	if conditions := node.get("conditions"); conditions != nil && v.expect(path+".conditions", conditions, kindArray) {
+		v.itemCount(path+".conditions", conditions, "conditions", MinConditions, MaxConditions)
+		for i, condition := range conditions.items {
+			v.condition(fmt.Sprintf("%s.conditions[%d]", path, i), condition)
+		}
+	}
+	if rules := node.get("rules"); rules != nil {
+		v.rule(path+".rules", rules)
+	}
+	v.activity(path, node)
+	v.stacking(path, node)
+	if autoApply := node.get("autoApply"); autoApply != nil {
+		v.expect(path+".autoApply", autoApply, kindBool)
+	}
+	v.redemptionLimits(path, node)
+}
+
+func (v *schemaValidator) redemptionLimits(path string, node *schemaNode) {
+	for _, key := range []string{"maxRedemptions", "maxPerCustomer"} {
+		limit := node.get(key)
+		if limit != nil && v.expect(path+"."+key, limit, kindNumber) && (limit.number < 1 || limit.number != float64(int(limit.number))) {
+			v.fail(path+"."+key, limit.line, limit.column, "should be a positive integer, received %v", limit.number)
+		}
+	}
+	total, perCustomer := node.get("maxRedemptions"), node.get("maxPerCustomer")
+	if total != nil && perCustomer != nil && total.kind == kindNumber && perCustomer.kind == kindNumber && total.number >= 1 && perCustomer.number > total.number {
+		v.fail(path+".maxPerCustomer", perCustomer.line, perCustomer.column, "should not exceed maxRedemptions")
+	}
+}
+
+func (v *schemaValidator) stacking(path string, node *schemaNode) {
+	if stacking := node.get("stacking"); stacking != nil && v.expect(path+".stacking", stacking, kindString) && !models.IsValidStacking(models.Stacking(stacking.text)) {
+		v.fail(path+".stacking", stacking.line, stacking.column, "unknown stacking %q, expected one of exclusive, stackable, bestOf", stacking.text)
+	}
+	if mode := node.get("stackMode"); mode != nil && v.expect(path+".stackMode", mode, kindString) && !models.IsValidStackMode(models.StackMode(mode.text)) {
+		v.fail(path+".stackMode", mode.line, mode.column, "unknown stack mode %q, expected one of additive, sequential", mode.text)
+	}
+	if priority := node.get("priority"); priority != nil && v.expect(path+".priority", priority, kindNumber) && priority.number != float64(int(priority.number)) {
+		v.fail(path+".priority", priority.line, priority.column, "should be an integer, received %v", priority.number)
+	}
+}
+
+// Discount as per its type (percentage by default), along with cap and floor
+func (v *schemaValidator) discount(path string, node *schemaNode) {
+	discountType := models.DiscountPercentage
+	if typeNode := node.get("discountType"); typeNode != nil && v.expect(path+".discountType", typeNode, kindString) {
+		discountType = models.DiscountType(typeNode.text)
+		if !models.IsValidDiscountType(discountType) {
+			v.fail(path+".discountType", typeNode.line, typeNode.column, "unknown discount type %q, expected one of percentage, flat", typeNode.text)
+			return
+		}
+	}
+
+	discount := node.get("discount")
+	if discount != nil && v.expect(path+".discount", discount, kindNumber) {
+		if discountType == models.DiscountFlat && discount.number < 0 {
+			v.fail(path+".discount", discount.line, discount.column, "flat discount should not be negative, received %v", discount.number)
+		}
+		if discountType == models.DiscountPercentage && (discount.number < MinDiscountRatio || discount.number > MaxDiscountRatio) {
+			v.fail(path+".discount", discount.line, discount.column, "should be between %d and %d, received %v", MinDiscountRatio, MaxDiscountRatio, discount.number)
+		}
+	}
+
+	for _, key := range []string{"minDiscount", "maxDiscount"} {
+		limit := node.get(key)
+		if limit == nil || !v.expect(path+"."+key, limit, kindNumber) {
+			continue
+		}
+		if discountType == models.DiscountFlat {
+			v.fail(path+"."+key, limit.line, limit.column, "supported by percentage discounts only")
+		} else if limit.number < 0 {
+			v.fail(path+"."+key, limit.line, limit.column, "should not be negative, received %v", limit.number)
+		}
+	}
+	min, max := node.get("minDiscount"), node.get("maxDiscount")
+	if discountType == models.DiscountPercentage && min != nil && max != nil && min.kind == kindNumber && max.kind == kindNumber && min.number > max.number {
+		v.fail(path+".minDiscount", min.line, min.column, "should not exceed maxDiscount")
+	}
+}
+
+// Validity period (RFC 3339 timestamps) and schedule
+func (v *schemaValidator) activity(path string, node *schemaNode) {
+	timestamps := make(map[string]time.Time)
+	for _, key := range []string{"validFrom", "validUntil"} {
+		value := node.get(key)
+		if value == nil || !v.expect(path+"."+key, value, kindString) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value.text)
+		if err != nil {
+			v.fail(path+"."+key, value.line, value.column, "should be RFC 3339 timestamp, received %q", value.text)
+			continue
+		}
+		timestamps[key] = t
+	}
+	from, hasFrom := timestamps["validFrom"]
+	until, hasUntil := timestamps["validUntil"]
+	if hasFrom && hasUntil && !from.Before(until) {
+		validUntil := node.get("validUntil")
+		v.fail(path+".validUntil", validUntil.line, validUntil.column, "should be after validFrom")
+	}
+
+	scheduleNode := node.get("schedule")
+	if scheduleNode == nil || !v.expect(path+".schedule", scheduleNode, kindObject) {
+		return
+	}
+	v.properties(path+".schedule", scheduleNode, "days", "from", "until")
+	schedule := models.OfferSchedule{}
+	if days := scheduleNode.get("days"); days != nil && v.expect(path+".schedule.days", days, kindArray) {
+		for i, day := range days.items {
+			if v.expect(fmt.Sprintf("%s.schedule.days[%d]", path, i), day, kindString) {
+				schedule.Days = append(schedule.Days, day.text)
+			}
+		}
+	}
+	if from := scheduleNode.get("from"); from != nil && v.expect(path+".schedule.from", from, kindString) {
+		schedule.From = from.text
+	}
+	if until := scheduleNode.get("until"); until != nil && v.expect(path+".schedule.until", until, kindString) {
+		schedule.Until = until.text
+	}
+	if reason := scheduleProblem(schedule); reason != "" {
+		v.fail(path+".schedule", scheduleNode.line, scheduleNode.column, "%s", reason)
+	}
+}
+
+// Exactly one of all, any, not or a condition
+func (v *schemaValidator) rule(path string, node *schemaNode) {
+	if !v.expect(path, node, kindObject) {
+		return
+	}
+	if node.get("fact") != nil || node.get("operator") != nil {
+		v.condition(path, node)
+		return
+	}
+	if len(node.members) != 1 || !contains([]string{"all", "any", "not"}, node.members[0].key) {
+		v.fail(path, node.line, node.column, "should be exactly one of all, any, not or a condition")
+		return
+	}
+
+	group := node.members[0]
+	childPath := path + "." + group.key
+	if group.key == "not" {
+		v.rule(childPath, group.value)
+		return
+	}
+	if !v.expect(childPath, group.value, kindArray) {
+		return
+	}
+	v.itemCount(childPath, group.value, "nodes", 1, 0)
+	for i, child := range group.value.items {
+		v.rule(fmt.Sprintf("%s[%d]", childPath, i), child)
+	}
+}
+
+func (v *schemaValidator) condition(path string, node *schemaNode) {
+	if !v.expect(path, node, kindObject) {
+		return
+	}
+	v.properties(path, node, "fact", "operator", "value", "values", "text", "exclusive")
+	v.required(path, node, "fact", "operator")
+
+	fact, operator := node.get("fact"), node.get("operator")
+	value, values, text := node.get("value"), node.get("values"), node.get("text")
+	if value != nil {
+		v.expect(path+".value", value, kindNumber)
+	}
+	if text != nil {
+		v.expect(path+".text", text, kindString)
+	}
+	if exclusive := node.get("exclusive"); exclusive != nil {
+		v.expect(path+".exclusive", exclusive, kindBool)
+	}
+	if values != nil && v.expect(path+".values", values, kindArray) {
+		for i, item := range values.items {
+			v.expect(fmt.Sprintf("%s.values[%d]", path, i), item, kindNumber)
+		}
+	}
+	if fact == nil || operator == nil || !v.expect(path+".fact", fact, kindString) || !v.expect(path+".operator", operator, kindString) {
+		return
+	}
+
+	definition, ok := LookupFact(fact.text)
+	if !ok {
+		v.fail(path+".fact", fact.line, fact.column, "unknown fact %q, expected one of %s", fact.text, strings.Join(RegisteredFacts(), ", "))
+		return
+	}
+	if !models.IsKnownOperator(operator.text) {
+		v.fail(path+".operator", operator.line, operator.column, "unknown operator %q, expected one of %s", operator.text, strings.Join(models.Operators, ", "))
+		return
+	}
+
+	if definition.Kind == FactKindText {
+		if operator.text != models.Equal && operator.text != models.NotEqual {
+			v.fail(path+".operator", operator.line, operator.column, "operator %q is not supported by text fact %q", operator.text, fact.text)
+		}
+		if text == nil {
+			v.fail(path, node.line, node.column, "missing required property %q", "text")
+		}
+		if value != nil || values != nil {
+			v.fail(path, node.line, node.column, "text fact %q is compared using text", fact.text)
+		}
+		return
+	}
+	if text != nil {
+		v.fail(path+".text", text.line, text.column, "number fact %q does not accept text", fact.text)
+	}
+
+	switch operator.text {
+	case models.Between:
+		if values == nil {
+			v.fail(path, node.line, node.column, "missing required property %q", "values")
+		} else if values.kind == kindArray && len(values.items) != 2 {
+			v.fail(path+".values", values.line, values.column, "between requires lower and upper bounds, received %d values", len(values.items))
+		} else if values.kind == kindArray && values.items[0].number > values.items[1].number {
+			v.fail(path+".values", values.line, values.column, "lower bound should not exceed upper bound")
+		}
+	case models.In:
+		if values == nil {
+			v.fail(path, node.line, node.column, "missing required property %q", "values")
+		} else if values.kind == kindArray && len(values.items) == 0 {
+			v.fail(path+".values", values.line, values.column, "in requires at least one value")
+		}
+	default:
+		if value == nil {
+			v.fail(path, node.line, node.column, "missing required property %q", "value")
+		}
+		if values != nil {
+			v.fail(path+".values", values.line, values.column, "%s does not accept values", operator.text)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Decodes json, recording position of every value
+func parseJSON(content []byte) (*schemaNode, error) {
+	p := &jsonParser{content: content, dec: json.NewDecoder(bytes.NewReader(content))}
+	p.dec.UseNumber()
+
+	root, err := p.value()
+	if err != nil {
+		return nil, p.syntaxError(err)
+	}
+	if _, err = p.dec.Token(); err != io.EOF {
+		line, column := p.position(p.dec.InputOffset())
+		return nil, SchemaErrors{{Path: "$", Line: line, Column: column, Message: "unexpected content after offers"}}
+	}
+	return root, nil
+}
+
+type jsonParser struct {
+	content []byte
+	dec     *json.Decoder
+}
+
+// Position (1 based) of the next value, skipping separators after the given offset
+func (p *jsonParser) position(offset int64) (int, int) {
+	for offset < int64(len(p.content)) && strings.ContainsRune(" \t\r\n,:", rune(p.content[offset])) {
+		offset++
+	}
+	consumed := p.content[:offset]
+	line := bytes.Count(consumed, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(consumed, '\n')
+	return line, column
+}
+
+func (p *jsonParser) syntaxError(err error) error {
+	var schemaErrs SchemaErrors
+	if errors.As(err, &schemaErrs) {
+		return err
+	}
+	offset := p.dec.InputOffset()
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	line, column := p.position(offset)
+	return SchemaErrors{{Path: "$", Line: line, Column: column, Message: err.Error()}}
+}
+
+func (p *jsonParser) value() (*schemaNode, error) {
+	line, column := p.position(p.dec.InputOffset())
+	token, err := p.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &schemaNode{line: line, column: column}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '{' {
+			node.kind = kindObject
+			for p.dec.More() {
+				keyLine, keyColumn := p.position(p.dec.InputOffset())
+				key, err := p.dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				node.members = append(node.members, member{key: key.(string), line: keyLine, column: keyColumn, value: value})
+			}
+		} else {
+			node.kind = kindArray
+			node.items = []*schemaNode{}
+			for p.dec.More() {
+				item, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				node.items = append(node.items, item)
+			}
+		}
+		// closing delimiter
+		if _, err := p.dec.Token(); err != nil {
+			return nil, err
+		}
+	case json.Number:
+		node.kind = kindNumber
+		node.number, err = t.Float64()
+		if err != nil {
+			return nil, err
+		}
+	case string:
+		node.kind = kindString
+		node.text = t
+	case bool:
+		node.kind = kindBool
+		node.boolean = t
+	case nil:
+		node.kind = kindNull
+	}
+	return node, nil
+}
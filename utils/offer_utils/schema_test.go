@@ -0,0 +1,200 @@
+package offer_utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateOffersSchema(t *testing.T) {
+	tt := []struct {
+		desc     string
+		content  string
+		expected []string
+	}{
+		{
+			desc:    "with valid offers",
+			content: `[{"code": "OFR001", "discount": 0.1, "conditions": [{"fact": "distance", "operator": "lessThan", "value": 200}]}]`,
+		},
+		{
+			desc:    "with valid rules",
+			content: `[{"code": "OFR001", "discount": 0.1, "rules": {"any": [{"fact": "distance", "operator": "between", "values": [10, 20]}, {"not": {"fact": "codePrefix", "operator": "equal", "text": "OFR"}}]}}]`,
+		},
+		{
+			desc:    "with validity period and schedule",
+			content: `[{"code": "A", "discount": 0.1, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}], "validFrom": "2026-12-01T00:00:00Z", "validUntil": "2027-01-01T00:00:00+05:30", "schedule": {"days": ["saturday"], "from": "22:00", "until": "02:00"}}]`,
+		},
+		{
+			desc:    "with invalid validity period and schedule",
+			content: `[{"code": "A", "discount": 0.1, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}], "validFrom": "2026-12-01", "validUntil": 1, "schedule": {"days": ["sat"], "at": "10:00"}}]`,
+			expected: []string{
+				`$[0].validFrom (line 1, column 118): should be RFC 3339 timestamp, received "2026-12-01"`,
+				"$[0].validUntil (line 1, column 146): should be string, received number",
+				`$[0].schedule.at (line 1, column 179): unknown property "at"`,
+				`$[0].schedule (line 1, column 161): unknown day "sat", expected one of monday, tuesday, wednesday, thursday, friday, saturday, sunday`,
+			},
+		},
+		{
+			desc:     "with validUntil before validFrom",
+			content:  `[{"code": "A", "discount": 0.1, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}], "validFrom": "2027-01-01T00:00:00Z", "validUntil": "2026-12-01T00:00:00Z"}]`,
+			expected: []string{"$[0].validUntil (line 1, column 156): should be after validFrom"},
+		},
+		{
+			desc:    "with flat and capped discounts",
+			content: `[{"code": "A", "discountType": "flat", "discount": 50, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}, {"code": "B", "discountType": "percentage", "discount": 0.1, "minDiscount": 20, "maxDiscount": 100, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+		},
+		{
+			desc:    "with invalid discounts",
+			content: `[{"code": "A", "discountType": "flat", "discount": -1, "maxDiscount": 10, "rules": {"fact": "weight", "operator": "lessThan", "value": 1}}, {"code": "B", "discount": 0.1, "minDiscount": 20, "maxDiscount": 10, "rules": {"fact": "weight", "operator": "lessThan", "value": 1}}, {"code": "C", "discountType": "bogo", "discount": 1, "rules": {"fact": "weight", "operator": "lessThan", "value": 1}}]`,
+			expected: []string{
+				"$[0].discount (line 1, column 52): flat discount should not be negative, received -1",
+				"$[0].maxDiscount (line 1, column 71): supported by percentage discounts only",
+				"$[1].minDiscount (line 1, column 187): should not exceed maxDiscount",
+				`$[2].discountType (line 1, column 306): unknown discount type "bogo", expected one of percentage, flat`,
+			},
+		},
+		{
+			desc:    "with stacking rules",
+			content: `[{"code": "A", "discount": 0.1, "stacking": "stackable", "stackMode": "sequential", "priority": 1, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+		},
+		{
+			desc:    "with invalid stacking rules",
+			content: `[{"code": "A", "discount": 0.1, "stacking": "all", "stackMode": "compound", "priority": 1.5, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{
+				`$[0].stacking (line 1, column 45): unknown stacking "all", expected one of exclusive, stackable, bestOf`,
+				`$[0].stackMode (line 1, column 65): unknown stack mode "compound", expected one of additive, sequential`,
+				"$[0].priority (line 1, column 89): should be an integer, received 1.5",
+			},
+		},
+		{
+			desc:    "with auto apply opt out",
+			content: `[{"code": "A", "discount": 0.1, "autoApply": false, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+		},
+		{
+			desc:     "with invalid auto apply",
+			content:  `[{"code": "A", "discount": 0.1, "autoApply": "no", "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{"$[0].autoApply (line 1, column 46): should be boolean, received string"},
+		},
+		{
+			desc:    "with redemption limits",
+			content: `[{"code": "A", "discount": 0.1, "maxRedemptions": 500, "maxPerCustomer": 3, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+		},
+		{
+			desc:     "with fractional redemption limit",
+			content:  `[{"code": "A", "discount": 0.1, "maxRedemptions": 2, "maxPerCustomer": 2.5, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{"$[0].maxPerCustomer (line 1, column 72): should be a positive integer, received 2.5", "$[0].maxPerCustomer (line 1, column 72): should not exceed maxRedemptions"},
+		},
+		{
+			desc:     "with zero redemption limit",
+			content:  `[{"code": "A", "discount": 0.1, "maxRedemptions": 0, "maxPerCustomer": 3, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{"$[0].maxRedemptions (line 1, column 51): should be a positive integer, received 0"},
+		},
+		{
+			desc:     "with per customer limit above total",
+			content:  `[{"code": "A", "discount": 0.1, "maxRedemptions": 2, "maxPerCustomer": 3, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{"$[0].maxPerCustomer (line 1, column 72): should not exceed maxRedemptions"},
+		},
+		{
+			desc:     "with object instead of offers",
+			content:  `{}`,
+			expected: []string{"$ (line 1, column 1): should be array, received object"},
+		},
+		{
+			desc:     "with no offers",
+			content:  `[]`,
+			expected: []string{"$ (line 1, column 1): should have at least 1 offers, received 0"},
+		},
+		{
+			desc:     "with too many offers",
+			content:  "[" + strings.Repeat(`{"code": "A", "discount": 0, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]},`, 50) + `{"code": "A", "discount": 0, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{"$ (line 1, column 1): should have at most 50 offers, received 51"},
+		},
+		{
+			desc:     "with too many conditions",
+			content:  `[{"code": "A", "discount": 0, "conditions": [` + strings.Repeat(`{"fact": "weight", "operator": "lessThan", "value": 1},`, 30) + `{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{"$[0].conditions (line 1, column 45): should have at most 30 conditions, received 31"},
+		},
+		{
+			desc:    "with missing and unknown properties",
+			content: "[\n  {\"code\": 1, \"discount\": 1.5, \"conditions\": [], \"extra\": true}\n]",
+			expected: []string{
+				`$[0].extra (line 2, column 50): unknown property "extra"`,
+				"$[0].code (line 2, column 12): should be string, received number",
+				"$[0].discount (line 2, column 27): should be between 0 and 1, received 1.5",
+				"$[0].conditions (line 2, column 46): should have at least 1 conditions, received 0",
+			},
+		},
+		{
+			desc:    "without conditions or rules",
+			content: `[{"code": "A"}]`,
+			expected: []string{
+				`$[0] (line 1, column 2): missing required property "discount"`,
+				"$[0] (line 1, column 2): requires conditions or rules",
+			},
+		},
+		{
+			desc:     "with duplicate property",
+			content:  `[{"code": "A", "code": "B", "discount": 0, "conditions": [{"fact": "weight", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{`$[0].code (line 1, column 16): duplicate property "code"`},
+		},
+		{
+			desc:     "with unknown fact",
+			content:  `[{"code": "A", "discount": 0, "conditions": [{"fact": "volume", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{fmt.Sprintf(`$[0].conditions[0].fact (line 1, column 55): unknown fact "volume", expected one of %s`, strings.Join(RegisteredFacts(), ", "))},
+		},
+		{
+			desc:    "with invalid operator values",
+			content: `[{"code": "A", "discount": 0, "conditions": [{"fact": "weight", "operator": "between", "values": [5, 1]}, {"fact": "weight", "operator": "in", "values": []}, {"fact": "weight", "operator": "lessThan"}]}]`,
+			expected: []string{
+				"$[0].conditions[0].values (line 1, column 98): lower bound should not exceed upper bound",
+				"$[0].conditions[1].values (line 1, column 154): in requires at least one value",
+				`$[0].conditions[2] (line 1, column 159): missing required property "value"`,
+			},
+		},
+		{
+			desc:    "with text fact compared as number",
+			content: `[{"code": "A", "discount": 0, "conditions": [{"fact": "codePrefix", "operator": "lessThan", "value": 1}]}]`,
+			expected: []string{
+				`$[0].conditions[0].operator (line 1, column 81): operator "lessThan" is not supported by text fact "codePrefix"`,
+				`$[0].conditions[0] (line 1, column 46): missing required property "text"`,
+				`$[0].conditions[0] (line 1, column 46): text fact "codePrefix" is compared using text`,
+			},
+		},
+		{
+			desc:     "with group and condition in the same rule",
+			content:  `[{"code": "A", "discount": 0, "rules": {"all": [], "any": []}}]`,
+			expected: []string{"$[0].rules (line 1, column 40): should be exactly one of all, any, not or a condition"},
+		},
+		{
+			desc:     "with empty group",
+			content:  `[{"code": "A", "discount": 0, "rules": {"not": {"all": []}}}]`,
+			expected: []string{"$[0].rules.not.all (line 1, column 56): should have at least 1 nodes, received 0"},
+		},
+		{
+			desc:     "with invalid json",
+			content:  "[\n  {\"code\": \"A\",}\n]",
+			expected: []string{"$ (line 2, column 16): invalid character ',' looking for beginning of value"},
+		},
+		{
+			desc:     "with truncated json",
+			content:  `[{"code": "A"`,
+			expected: []string{"$ (line 1, column 14): unexpected end of JSON input"},
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffersSchema([]byte(test.content))
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			expected := strings.Join(test.expected, "\n")
+			if err == nil || err.Error() != expected {
+				t.Errorf("expected\n%v\nreceived\n%v", expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package offer_utils
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+// Decides which of the applicable offers (of a package) are applied, and their discount.
+//
+// Stackable offers are combined with the best of the bestOf offers, and applied in ascending order of priority
+// (additive offers on the delivery cost, sequential offers on the remaining amount).
+// The best exclusive offer is applied alone, when its discount is not less than the combined discount.
+//
+// Returns a result for every offer (in the given order), either applied or not combined along with the reason
+func StackOffers(offers []models.Offer, deliveryCost models.Money, rounding models.RoundingMode) []models.DiscountResult {
+	amounts := make([]models.Money, len(offers))
+	bestExclusive, bestOf := -1, -1
+	stack := []int{}
+	for i, offer := range offers {
+		amounts[i] = DiscountAmount(offer, deliveryCost, rounding)
+		switch offer.StackingRule() {
+		case models.StackingStackable:
+			stack = append(stack, i)
+		case models.StackingBestOf:
+			if bestOf < 0 || amounts[i] > amounts[bestOf] {
+				bestOf = i
+			}
+		default:
+			if bestExclusive < 0 || amounts[i] > amounts[bestExclusive] {
+				bestExclusive = i
+			}
+		}
+	}
+	if bestOf >= 0 {
+		stack = append(stack, bestOf)
+	}
+	sort.SliceStable(stack, func(a, b int) bool {
+		if offers[stack[a]].Priority != offers[stack[b]].Priority {
+			return offers[stack[a]].Priority < offers[stack[b]].Priority
+		}
+		return stack[a] < stack[b]
+	})
+
+	stacked := make(map[int]models.Money, len(stack))
+	var total models.Money
+	remaining := deliveryCost
+	for _, i := range stack {
+		amount := amounts[i]
+		if offers[i].Mode() == models.StackSequential {
+			amount = DiscountAmount(offers[i], remaining, rounding)
+		}
+		if amount > remaining {
+			amount = remaining
+		}
+		remaining = remaining.Sub(amount)
+		total = total.Add(amount)
+		stacked[i] = amount
+	}
+
+	results := make([]models.DiscountResult, len(offers))
+	for i, offer := range offers {
+		results[i] = models.DiscountResult{Code: offer.Code, Status: models.DiscountNotCombined}
+	}
+	if bestExclusive >= 0 && (len(stack) == 0 || amounts[bestExclusive] >= total) {
+		for i := range results {
+			results[i].Reason = fmt.Sprintf("not combinable with %s (exclusive)", offers[bestExclusive].Code)
+		}
+		results[bestExclusive] = models.DiscountResult{Code: offers[bestExclusive].Code, Amount: amounts[bestExclusive], Status: models.DiscountApplied}
+		return results
+	}
+
+	for i, offer := range offers {
+		switch {
+		case offer.StackingRule() == models.StackingExclusive:
+			results[i].Reason = "exclusive offer, combined offers give a higher discount"
+		case offer.StackingRule() == models.StackingBestOf && i != bestOf:
+			results[i].Reason = fmt.Sprintf("%s gives a higher discount (bestOf)", offers[bestOf].Code)
+		default:
+			results[i] = models.DiscountResult{Code: offer.Code, Amount: stacked[i], Status: models.DiscountApplied}
+		}
+	}
+	return results
+}
+
+// Describes what is wrong with the stacking rule of the offer, empty when it is valid
+func stackingProblem(offer models.Offer) string {
+	if !models.IsValidStacking(offer.StackingRule()) {
+		return fmt.Sprintf("unknown stacking %q, expected one of exclusive, stackable, bestOf", offer.Stacking)
+	}
+	if !models.IsValidStackMode(offer.Mode()) {
+		return fmt.Sprintf("unknown stack mode %q, expected one of additive, sequential", offer.StackMode)
+	}
+	return ""
+}
+
+func validateStacking(offer models.Offer) error {
+	if reason := stackingProblem(offer); reason != "" {
+		return error_utils.ErrOfferStacking(offer.Code, reason)
+	}
+	return nil
+}
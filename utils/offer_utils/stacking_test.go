@@ -0,0 +1,148 @@
+package offer_utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+func TestStackOffers(t *testing.T) {
+	applied := func(code models.OfferCode, amount models.Money) models.DiscountResult {
+		return models.DiscountResult{Code: code, Amount: amount, Status: models.DiscountApplied}
+	}
+	notCombined := func(code models.OfferCode, reason string) models.DiscountResult {
+		return models.DiscountResult{Code: code, Status: models.DiscountNotCombined, Reason: reason}
+	}
+
+	tt := []struct {
+		desc     string
+		offers   []models.Offer
+		expected []models.DiscountResult
+	}{
+		{
+			desc:     "single offer",
+			offers:   []models.Offer{{Code: "A", Discount: 0.1}},
+			expected: []models.DiscountResult{applied("A", 1000)},
+		},
+		{
+			desc:   "exclusive offers, best one is applied",
+			offers: []models.Offer{{Code: "A", Discount: 0.1}, {Code: "B", Discount: 0.2}},
+			expected: []models.DiscountResult{
+				notCombined("A", "not combinable with B (exclusive)"),
+				applied("B", 2000),
+			},
+		},
+		{
+			desc: "stackable offers are additive by default",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Stacking: models.StackingStackable},
+				{Code: "B", Discount: 0.2, Stacking: models.StackingStackable},
+			},
+			expected: []models.DiscountResult{applied("A", 1000), applied("B", 2000)},
+		},
+		{
+			desc: "sequential offers apply on the remaining amount, in order of priority",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Stacking: models.StackingStackable, StackMode: models.StackSequential, Priority: 2},
+				{Code: "B", Discount: 0.2, Stacking: models.StackingStackable, StackMode: models.StackSequential, Priority: 1},
+			},
+			// B: 20% of 100, A: 10% of 80
+			expected: []models.DiscountResult{applied("A", 800), applied("B", 2000)},
+		},
+		{
+			desc: "stacked discount does not exceed delivery cost",
+			offers: []models.Offer{
+				{Code: "A", Discount: 60, DiscountType: models.DiscountFlat, Stacking: models.StackingStackable},
+				{Code: "B", Discount: 60, DiscountType: models.DiscountFlat, Stacking: models.StackingStackable},
+			},
+			expected: []models.DiscountResult{applied("A", 6000), applied("B", 4000)},
+		},
+		{
+			desc: "best of bestOf offers is stacked",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Stacking: models.StackingBestOf},
+				{Code: "B", Discount: 0.3, Stacking: models.StackingBestOf},
+				{Code: "C", Discount: 0.05, Stacking: models.StackingStackable},
+			},
+			expected: []models.DiscountResult{
+				notCombined("A", "B gives a higher discount (bestOf)"),
+				applied("B", 3000),
+				applied("C", 500),
+			},
+		},
+		{
+			desc: "exclusive offer with higher discount than combined offers",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Stacking: models.StackingStackable},
+				{Code: "B", Discount: 0.5},
+				{Code: "C", Discount: 0.1, Stacking: models.StackingStackable},
+			},
+			expected: []models.DiscountResult{
+				notCombined("A", "not combinable with B (exclusive)"),
+				applied("B", 5000),
+				notCombined("C", "not combinable with B (exclusive)"),
+			},
+		},
+		{
+			desc: "combined offers with higher discount than exclusive offer",
+			offers: []models.Offer{
+				{Code: "A", Discount: 0.1, Stacking: models.StackingStackable},
+				{Code: "B", Discount: 0.15},
+				{Code: "C", Discount: 0.1, Stacking: models.StackingStackable},
+			},
+			expected: []models.DiscountResult{
+				applied("A", 1000),
+				notCombined("B", "exclusive offer, combined offers give a higher discount"),
+				applied("C", 1000),
+			},
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result := StackOffers(test.offers, 10000, models.RoundHalfUp)
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %+v, received %+v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateOffersStacking(t *testing.T) {
+	conditions := []models.Condition{{Fact: "weight", Operator: models.LessThan, Value: 10}}
+	tt := []struct {
+		desc     string
+		offer    models.Offer
+		expected error
+	}{
+		{
+			desc:  "with stacking rules",
+			offer: models.Offer{Code: "A", Conditions: conditions, Stacking: models.StackingBestOf, StackMode: models.StackSequential, Priority: 1},
+		},
+		{
+			desc:     "with unknown stacking",
+			offer:    models.Offer{Code: "A", Conditions: conditions, Stacking: "all"},
+			expected: error_utils.ErrOfferStacking("A", `unknown stacking "all", expected one of exclusive, stackable, bestOf`),
+		},
+		{
+			desc:     "with unknown stack mode",
+			offer:    models.Offer{Code: "A", Conditions: conditions, StackMode: "compound"},
+			expected: error_utils.ErrOfferStacking("A", `unknown stack mode "compound", expected one of additive, sequential`),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOffers([]models.Offer{test.offer})
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
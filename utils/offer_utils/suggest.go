@@ -0,0 +1,58 @@
+package offer_utils
+
+import (
+	"strings"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+// Known codes within this many edits (insert, delete, substitute) of an unknown code are suggested
+const MaxSuggestionDistance = 2
+
+// Known code closest to the (unknown) code, ex: OFR001 for OFR0O1
+// Codes are compared case insensitively, the first one wins on a tie, nothing is suggested when no code is close enough
+func SuggestCode(code models.OfferCode, known []models.OfferCode) (models.OfferCode, bool) {
+	target := strings.ToUpper(string(code))
+	var suggestion models.OfferCode
+	best := MaxSuggestionDistance + 1
+	for _, candidate := range known {
+		distance := editDistance(target, strings.ToUpper(string(candidate)))
+		// a short code is within reach of almost every code
+		if distance < best && distance < len([]rune(target)) {
+			suggestion, best = candidate, distance
+		}
+	}
+	return suggestion, suggestion != ""
+}
+
+// Levenshtein distance
+func editDistance(a string, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = minOf(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
+
+func minOf(values ...int) int {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
@@ -0,0 +1,50 @@
+package offer_utils
+
+import (
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+)
+
+func TestSuggestCode(t *testing.T) {
+	known := []models.OfferCode{"OFR001", "OFR002", "OFR003", "WEEKEND"}
+	tt := []struct {
+		code     models.OfferCode
+		expected models.OfferCode
+	}{
+		{code: "OFR0O1", expected: "OFR001"},
+		{code: "ofr003", expected: "OFR003"},
+		{code: "OFR01", expected: "OFR001"},
+		{code: "WEEKND", expected: "WEEKEND"},
+		{code: "OFR00", expected: "OFR001"},
+		{code: "SUMMER"},
+		{code: "NA"},
+	}
+	for _, tc := range tt {
+		t.Run(string(tc.code), func(t *testing.T) {
+			got, ok := SuggestCode(tc.code, known)
+			if got != tc.expected || ok != (tc.expected != "") {
+				t.Errorf("SuggestCode() = %v %v, want %v", got, ok, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tt := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"OFR001", "OFR001", 0},
+		{"OFR0O1", "OFR001", 1},
+		{"OFR01", "OFR001", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+	for _, tc := range tt {
+		if got := editDistance(tc.a, tc.b); got != tc.expected {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package offer_utils
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// Root key of the offers in toml, ex: [[offers]]
+const tomlOffersKey = "offers"
+
+// Decodes toml (offers as an array of tables), recording position of every value
+func parseTOML(content []byte) (*schemaNode, error) {
+	b := &tomlBuilder{root: &schemaNode{kind: kindObject, line: 1, column: 1}}
+	b.parser.Reset(content)
+	b.current = b.root
+	for b.parser.NextExpression() {
+		if err := b.expression(b.parser.Expression()); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.parser.Error(); err != nil {
+		return nil, b.syntaxError(err)
+	}
+
+	var errs SchemaErrors
+	var offers *schemaNode
+	for _, m := range b.root.members {
+		if m.key != tomlOffersKey {
+			errs = append(errs, SchemaError{Path: "$." + m.key, Line: m.line, Column: m.column, Message: "unknown property " + strconv.Quote(m.key)})
+		} else if offers == nil {
+			offers = m.value
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if offers == nil {
+		return nil, SchemaErrors{{Path: "$", Line: 1, Column: 1, Message: "missing required property " + strconv.Quote(tomlOffersKey)}}
+	}
+	return offers, nil
+}
+
+type tomlBuilder struct {
+	parser  unstable.Parser
+	root    *schemaNode
+	current *schemaNode // table of the latest [table] or [[array table]] header
+}
+
+func (b *tomlBuilder) position(r unstable.Range) (int, int) {
+	start := b.parser.Shape(r).Start
+	return start.Line, start.Column
+}
+
+func (b *tomlBuilder) syntaxError(err error) error {
+	var parserErr *unstable.ParserError
+	if errors.As(err, &parserErr) && len(parserErr.Highlight) > 0 {
+		line, column := b.position(b.parser.Range(parserErr.Highlight))
+		return SchemaErrors{{Path: "$", Line: line, Column: column, Message: parserErr.Message}}
+	}
+	return SchemaErrors{{Path: "$", Line: 1, Column: 1, Message: err.Error()}}
+}
+
+func (b *tomlBuilder) expression(expr *unstable.Node) error {
+	switch expr.Kind {
+	case unstable.KeyValue:
+		return b.keyValue(b.current, expr)
+	case unstable.Table, unstable.ArrayTable:
+		table, err := b.table(expr)
+		if err != nil {
+			return err
+		}
+		b.current = table
+	}
+	return nil
+}
+
+// Table of a header, tables of the dotted key are created when not defined yet
+// [a.b] descends into the latest table of array a, [[a.b]] appends a table to array b
+func (b *tomlBuilder) table(expr *unstable.Node) (*schemaNode, error) {
+	node := b.root
+	keys := expr.Key()
+	for keys.Next() {
+		key := keys.Node()
+		line, column := b.position(key.Raw)
+		last := keys.IsLast()
+		existing := node.get(string(key.Data))
+
+		switch {
+		case existing == nil && last && expr.Kind == unstable.ArrayTable:
+			existing = &schemaNode{kind: kindArray, line: line, column: column, items: []*schemaNode{}}
+			node.members = append(node.members, member{key: string(key.Data), line: line, column: column, value: existing})
+		case existing == nil:
+			existing = &schemaNode{kind: kindObject, line: line, column: column}
+			node.members = append(node.members, member{key: string(key.Data), line: line, column: column, value: existing})
+		}
+
+		switch {
+		case last && expr.Kind == unstable.ArrayTable && existing.kind == kindArray:
+			table := &schemaNode{kind: kindObject, line: line, column: column}
+			existing.items = append(existing.items, table)
+			return table, nil
+		case !last && existing.kind == kindArray && len(existing.items) > 0:
+			node = existing.items[len(existing.items)-1]
+		case existing.kind == kindObject:
+			node = existing
+		default:
+			return nil, SchemaErrors{{Path: "$", Line: line, Column: column, Message: "key " + strconv.Quote(string(key.Data)) + " is already defined"}}
+		}
+	}
+	return node, nil
+}
+
+// Adds the value to the table, tables of a dotted key (a.b = 1) are created when not defined yet
+func (b *tomlBuilder) keyValue(table *schemaNode, expr *unstable.Node) error {
+	keys := expr.Key()
+	for keys.Next() {
+		key := keys.Node()
+		line, column := b.position(key.Raw)
+		if keys.IsLast() {
+			value, err := b.value(expr.Value(), line, column)
+			if err != nil {
+				return err
+			}
+			// duplicate keys are reported by the schema validator
+			table.members = append(table.members, member{key: string(key.Data), line: line, column: column, value: value})
+			return nil
+		}
+		existing := table.get(string(key.Data))
+		if existing == nil {
+			existing = &schemaNode{kind: kindObject, line: line, column: column}
+			table.members = append(table.members, member{key: string(key.Data), line: line, column: column, value: existing})
+		}
+		if existing.kind != kindObject {
+			return SchemaErrors{{Path: "$", Line: line, Column: column, Message: "key " + strconv.Quote(string(key.Data)) + " is already defined"}}
+		}
+		table = existing
+	}
+	return nil
+}
+
+// Value along with its position, values without a position (ex: booleans, arrays) take the position of their key
+func (b *tomlBuilder) value(value *unstable.Node, line int, column int) (*schemaNode, error) {
+	if value.Raw.Length > 0 {
+		line, column = b.position(value.Raw)
+	}
+	node := &schemaNode{line: line, column: column}
+
+	switch value.Kind {
+	case unstable.String:
+		node.kind = kindString
+		node.text = string(value.Data)
+	case unstable.Bool:
+		node.kind = kindBool
+		node.boolean = string(value.Data) == "true"
+	case unstable.Integer:
+		node.kind = kindNumber
+		number, err := strconv.ParseInt(strings.ReplaceAll(string(value.Data), "_", ""), 0, 64)
+		if err != nil {
+			return nil, SchemaErrors{{Path: "$", Line: line, Column: column, Message: err.Error()}}
+		}
+		node.number = float64(number)
+	case unstable.Float:
+		node.kind = kindNumber
+		number, err := strconv.ParseFloat(strings.ReplaceAll(string(value.Data), "_", ""), 64)
+		if err != nil {
+			return nil, SchemaErrors{{Path: "$", Line: line, Column: column, Message: err.Error()}}
+		}
+		if math.IsInf(number, 0) || math.IsNaN(number) {
+			return nil, SchemaErrors{{Path: "$", Line: line, Column: column, Message: "number should be finite, received " + string(value.Data)}}
+		}
+		node.number = number
+	case unstable.Array:
+		node.kind = kindArray
+		node.items = []*schemaNode{}
+		items := value.Children()
+		for items.Next() {
+			item, err := b.value(items.Node(), line, column)
+			if err != nil {
+				return nil, err
+			}
+			node.items = append(node.items, item)
+		}
+	case unstable.InlineTable:
+		node.kind = kindObject
+		entries := value.Children()
+		for entries.Next() {
+			if err := b.keyValue(node, entries.Node()); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		// dates and times (validFrom, validUntil) as written, ex: 2026-12-01T00:00:00Z
+		node.kind = kindString
+		node.text = strings.Replace(string(value.Data), " ", "T", 1)
+	}
+	return node, nil
+}
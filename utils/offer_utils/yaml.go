@@ -0,0 +1,107 @@
+package offer_utils
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ex: yaml: line 3: mapping values are not allowed in this context
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// Decodes yaml (a sequence of offers), recording position of every value
+func parseYAML(content []byte) (*schemaNode, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	var document yaml.Node
+	if err := dec.Decode(&document); err != nil {
+		if err == io.EOF {
+			// empty document
+			return &schemaNode{kind: kindNull, line: 1, column: 1}, nil
+		}
+		return nil, yamlSyntaxError(err)
+	}
+	var next yaml.Node
+	if err := dec.Decode(&next); err != io.EOF {
+		if err != nil {
+			return nil, yamlSyntaxError(err)
+		}
+		return nil, SchemaErrors{{Path: "$", Line: next.Line, Column: next.Column, Message: "unexpected content after offers"}}
+	}
+	return yamlNode(&document)
+}
+
+// yaml reports the line (not the column) of syntax errors
+func yamlSyntaxError(err error) error {
+	message := err.Error()
+	line := 1
+	if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+		line, _ = strconv.Atoi(match[1])
+		message = strings.TrimPrefix(message, match[0])
+	}
+	return SchemaErrors{{Path: "$", Line: line, Message: strings.TrimPrefix(message, "yaml: ")}}
+}
+
+func yamlNode(node *yaml.Node) (*schemaNode, error) {
+	for node.Kind == yaml.DocumentNode && len(node.Content) > 0 || node.Kind == yaml.AliasNode {
+		if node.Kind == yaml.DocumentNode {
+			node = node.Content[0]
+		} else {
+			node = node.Alias
+		}
+	}
+	result := &schemaNode{line: node.Line, column: node.Column}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		result.kind = kindObject
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			value, err := yamlNode(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			result.members = append(result.members, member{key: key.Value, line: key.Line, column: key.Column, value: value})
+		}
+	case yaml.SequenceNode:
+		result.kind = kindArray
+		result.items = []*schemaNode{}
+		for _, item := range node.Content {
+			value, err := yamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+			result.items = append(result.items, value)
+		}
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			result.kind = kindNull
+		case "!!bool":
+			result.kind = kindBool
+			if err := node.Decode(&result.boolean); err != nil {
+				return nil, err
+			}
+		case "!!int", "!!float":
+			result.kind = kindNumber
+			if err := node.Decode(&result.number); err != nil {
+				return nil, err
+			}
+			if math.IsInf(result.number, 0) || math.IsNaN(result.number) {
+				return nil, SchemaErrors{{Path: "$", Line: node.Line, Column: node.Column, Message: "number should be finite, received " + node.Value}}
+			}
+		default:
+			// strings, and timestamps (validFrom, validUntil) as written
+			result.kind = kindString
+			result.text = node.Value
+		}
+	default:
+		// empty document
+		result.kind = kindNull
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package rate_card_utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+// Schema check for a rate card, every rate should be a non negative number
+func ValidateRateCard(card models.RateCard) error {
+	if len(strings.TrimSpace(card.Name)) == 0 {
+		return error_utils.ErrRateCardName
+	}
+	if card.PerKg < 0 {
+		return error_utils.ErrRateCardNegativeValue("perKg", card.PerKg)
+	}
+	if card.PerKm < 0 {
+		return error_utils.ErrRateCardNegativeValue("perKm", card.PerKm)
+	}
+	if card.MinimumCharge < 0 {
+		return error_utils.ErrRateCardNegativeValue("minimumCharge", card.MinimumCharge)
+	}
+	if card.Rounding != "" && !models.IsValidRoundingMode(card.Rounding) {
+		return error_utils.ErrRateCardRounding(card.Rounding)
+	}
+	if card.WeightSlabs != nil {
+		if err := validateSlabs(models.FactWeight, *card.WeightSlabs); err != nil {
+			return err
+		}
+	}
+	if card.DistanceSlabs != nil {
+		if err := validateSlabs(models.FactDistance, *card.DistanceSlabs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Slabs should be contiguous, starting at 0 and the last one open ended, so that every value is priced
+func validateSlabs(fact string, pricing models.SlabPricing) error {
+	if pricing.Mode != models.SlabModeCumulative && pricing.Mode != models.SlabModeFlat {
+		return error_utils.ErrRateCardSlabMode(fact, pricing.Mode)
+	}
+	if len(pricing.Slabs) == 0 {
+		return error_utils.ErrRateCardSlab(fact, 0, "is required")
+	}
+	var previousTo float64
+	for i, slab := range pricing.Slabs {
+		isLast := i == len(pricing.Slabs)-1
+		if slab.From != previousTo {
+			return error_utils.ErrRateCardSlab(fact, i, "should start where the previous slab ends")
+		}
+		if slab.Rate < 0 {
+			return error_utils.ErrRateCardSlab(fact, i, "rate should not be negative")
+		}
+		if isLast && slab.To != 0 {
+			return error_utils.ErrRateCardSlab(fact, i, "should be open ended")
+		}
+		if !isLast && slab.To <= slab.From {
+			return error_utils.ErrRateCardSlab(fact, i, "should end after it starts")
+		}
+		previousTo = slab.To
+	}
+	return nil
+}
+
+func LoadRateCard(filename string) (models.RateCard, error) {
+	if len(strings.TrimSpace(filename)) == 0 {
+		return models.RateCard{}, error_utils.ErrMissingInput
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return models.RateCard{}, err
+	}
+
+	var card models.RateCard
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	// unknown keys are most likely typos of a rate, which should not be ignored
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&card); err != nil {
+		return models.RateCard{}, err
+	}
+
+	if err = ValidateRateCard(card); err != nil {
+		return models.RateCard{}, err
+	}
+	return card, nil
+}
@@ -0,0 +1,170 @@
+package rate_card_utils
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/lakshmaji/delivery-shell/models"
+	"github.com/lakshmaji/delivery-shell/utils/error_utils"
+)
+
+func TestValidateRateCard(t *testing.T) {
+	tt := []struct {
+		desc     string
+		card     models.RateCard
+		expected error
+	}{
+		{
+			desc:     "default rate card",
+			card:     models.DefaultRateCard(),
+			expected: nil,
+		},
+		{
+			desc:     "when name is missing",
+			card:     models.RateCard{PerKg: 10, PerKm: 5},
+			expected: error_utils.ErrRateCardName,
+		},
+		{
+			desc:     "when per kg rate is negative",
+			card:     models.RateCard{Name: "test", PerKg: -10, PerKm: 5},
+			expected: error_utils.ErrRateCardNegativeValue("perKg", -10),
+		},
+		{
+			desc:     "when per km rate is negative",
+			card:     models.RateCard{Name: "test", PerKg: 10, PerKm: -5},
+			expected: error_utils.ErrRateCardNegativeValue("perKm", -5),
+		},
+		{
+			desc:     "when minimum charge is negative",
+			card:     models.RateCard{Name: "test", PerKg: 10, PerKm: 5, MinimumCharge: -1},
+			expected: error_utils.ErrRateCardNegativeValue("minimumCharge", -1),
+		},
+		{
+			desc:     "with rounding mode",
+			card:     models.RateCard{Name: "test", Rounding: models.RoundHalfEven},
+			expected: nil,
+		},
+		{
+			desc:     "when rounding mode is unknown",
+			card:     models.RateCard{Name: "test", Rounding: "ceil"},
+			expected: error_utils.ErrRateCardRounding("ceil"),
+		},
+		{
+			desc: "with valid slabs",
+			card: models.RateCard{
+				Name:          "slabs",
+				WeightSlabs:   &models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: []models.Slab{{From: 0, To: 5, Rate: 10}, {From: 5, Rate: 8}}},
+				DistanceSlabs: &models.SlabPricing{Mode: models.SlabModeFlat, Slabs: []models.Slab{{From: 0, Rate: 5}}},
+			},
+			expected: nil,
+		},
+		{
+			desc:     "when slab mode is unknown",
+			card:     models.RateCard{Name: "slabs", WeightSlabs: &models.SlabPricing{Mode: "step", Slabs: []models.Slab{{Rate: 10}}}},
+			expected: error_utils.ErrRateCardSlabMode("weight", "step"),
+		},
+		{
+			desc:     "when slabs are empty",
+			card:     models.RateCard{Name: "slabs", DistanceSlabs: &models.SlabPricing{Mode: models.SlabModeFlat}},
+			expected: error_utils.ErrRateCardSlab("distance", 0, "is required"),
+		},
+		{
+			desc:     "when slabs are not contiguous",
+			card:     models.RateCard{Name: "slabs", WeightSlabs: &models.SlabPricing{Mode: models.SlabModeFlat, Slabs: []models.Slab{{From: 0, To: 5, Rate: 10}, {From: 6, Rate: 8}}}},
+			expected: error_utils.ErrRateCardSlab("weight", 1, "should start where the previous slab ends"),
+		},
+		{
+			desc:     "when slab rate is negative",
+			card:     models.RateCard{Name: "slabs", WeightSlabs: &models.SlabPricing{Mode: models.SlabModeFlat, Slabs: []models.Slab{{From: 0, Rate: -1}}}},
+			expected: error_utils.ErrRateCardSlab("weight", 0, "rate should not be negative"),
+		},
+		{
+			desc:     "when last slab is not open ended",
+			card:     models.RateCard{Name: "slabs", WeightSlabs: &models.SlabPricing{Mode: models.SlabModeFlat, Slabs: []models.Slab{{From: 0, To: 5, Rate: 10}}}},
+			expected: error_utils.ErrRateCardSlab("weight", 0, "should be open ended"),
+		},
+		{
+			desc:     "when slab ends before it starts",
+			card:     models.RateCard{Name: "slabs", WeightSlabs: &models.SlabPricing{Mode: models.SlabModeFlat, Slabs: []models.Slab{{From: 0, To: 0, Rate: 10}, {From: 0, Rate: 8}}}},
+			expected: error_utils.ErrRateCardSlab("weight", 0, "should end after it starts"),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateRateCard(test.card)
+			if test.expected == nil {
+				if err != nil {
+					t.Errorf("should not return error, received %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected.Error() {
+				t.Errorf("expected %v, received %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestLoadRateCard(t *testing.T) {
+	tt := []struct {
+		desc        string
+		file        string
+		expectedErr error
+		expected    models.RateCard
+	}{
+		{
+			desc:        "when rate card file is empty",
+			file:        "",
+			expectedErr: error_utils.ErrMissingInput,
+		},
+		{
+			desc:     "when rate card file is valid",
+			file:     "./testdata/rate_card.json",
+			expected: models.RateCard{Name: "express", PerKg: 12.5, PerKm: 6, MinimumCharge: 150},
+		},
+		{
+			desc:        "when rate card file is not available",
+			file:        "./testdata/no_rate_card.json",
+			expectedErr: &os.PathError{Op: "open", Path: "./testdata/no_rate_card.json", Err: errors.New("no such file or directory")},
+		},
+		{
+			desc:        "when rate card has unknown fields",
+			file:        "./testdata/unknown_field.json",
+			expectedErr: errors.New("json: unknown field \"perKilo\""),
+		},
+		{
+			desc: "when rate card has slabs",
+			file: "./testdata/slabs.json",
+			expected: models.RateCard{
+				Name:          "slabs",
+				MinimumCharge: 100,
+				WeightSlabs:   &models.SlabPricing{Mode: models.SlabModeCumulative, Slabs: []models.Slab{{From: 0, To: 5, Rate: 12}, {From: 5, To: 20, Rate: 10}, {From: 20, Rate: 8}}},
+				DistanceSlabs: &models.SlabPricing{Mode: models.SlabModeFlat, Slabs: []models.Slab{{From: 0, To: 50, Rate: 6}, {From: 50, Rate: 4}}},
+			},
+		},
+		{
+			desc:        "when rate card has negative rates",
+			file:        "./testdata/negative_rate.json",
+			expectedErr: error_utils.ErrRateCardNegativeValue("perKg", -2),
+		},
+	}
+	for _, test := range tt {
+		t.Run(test.desc, func(t *testing.T) {
+			result, err := LoadRateCard(test.file)
+			if test.expectedErr != nil {
+				if err == nil || err.Error() != test.expectedErr.Error() {
+					t.Errorf("expected %v, received %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("should not return error, received %v", err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %v received %v", test.expected, result)
+			}
+		})
+	}
+}